@@ -0,0 +1,55 @@
+package scan
+
+import (
+	"database/sql/driver"
+	"maps"
+	"strings"
+)
+
+var (
+	lenientTruthy = []string{"1", "t", "true", "y", "yes", "on"}
+	lenientFalsy  = []string{"0", "f", "false", "n", "no", "off"}
+)
+
+// WithBoolValues accepts the given words, compared case-insensitively and
+// ignoring surrounding whitespace, as true and false for bool destinations,
+// in addition to the values accepted by default. Words are merged with those
+// of earlier WithBoolValues and WithLenientBools options; a word given again
+// takes its latest meaning.
+func WithBoolValues(truthy, falsy []string) Option {
+	return func(sc *Scanner) {
+		words := make(map[string]bool, len(sc.boolWords)+len(truthy)+len(falsy))
+		maps.Copy(words, sc.boolWords)
+		for _, w := range truthy {
+			words[strings.ToLower(strings.TrimSpace(w))] = true
+		}
+		for _, w := range falsy {
+			words[strings.ToLower(strings.TrimSpace(w))] = false
+		}
+		sc.boolWords = words
+	}
+}
+
+// WithLenientBools accepts "y"/"n", "yes"/"no" and "on"/"off" for bool destinations.
+func WithLenientBools() Option {
+	return WithBoolValues(lenientTruthy, lenientFalsy)
+}
+
+// convertBool converts src the way driver.Bool does, additionally accepting
+// single byte BIT(1) values and the words configured with WithBoolValues.
+// It is used for bool, named bool, sql.NullBool and sql.Null[bool] destinations.
+func (sc *Scanner) convertBool(src any) (bool, error) {
+	if b, ok := src.([]byte); ok && len(b) == 1 && b[0] <= 1 {
+		return b[0] == 1, nil
+	}
+	if sc.boolWords != nil && isText(src) {
+		if v, ok := sc.boolWords[strings.ToLower(strings.TrimSpace(asString(src)))]; ok {
+			return v, nil
+		}
+	}
+	bv, err := driver.Bool.ConvertValue(src)
+	if err != nil {
+		return false, err
+	}
+	return bv.(bool), nil
+}
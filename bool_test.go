@@ -0,0 +1,84 @@
+package scan
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestConvertBoolVocabulary(t *testing.T) {
+	tests := []struct {
+		opts    []Option
+		src     any
+		want    bool
+		wanterr string
+	}{
+		{opts: []Option{WithLenientBools()}, src: "Y", want: true},
+		{opts: []Option{WithLenientBools()}, src: []byte("n"), want: false},
+		{opts: []Option{WithLenientBools()}, src: " Yes ", want: true},
+		{opts: []Option{WithLenientBools()}, src: "OFF", want: false},
+		{opts: []Option{WithLenientBools()}, src: "TRUE", want: true},
+		{opts: []Option{WithLenientBools()}, src: int64(1), want: true},
+		{opts: []Option{WithLenientBools()}, src: []byte{1}, want: true},
+		{opts: []Option{WithLenientBools()}, src: "maybe", wanterr: `sql/driver: couldn't convert "maybe" into type bool`},
+		{opts: []Option{WithBoolValues([]string{"ja"}, []string{"nein"})}, src: "JA", want: true},
+		{opts: []Option{WithBoolValues([]string{"ja"}, []string{"nein"})}, src: "Nein", want: false},
+		{opts: []Option{WithBoolValues([]string{"ja"}, []string{"nein"})}, src: "1", want: true},
+		{opts: []Option{WithBoolValues([]string{"ja"}, []string{"nein"})}, src: "yes", wanterr: `sql/driver: couldn't convert "yes" into type bool`},
+		{opts: []Option{WithLenientBools(), WithBoolValues([]string{"ja"}, []string{"nein"})}, src: "yes", want: true},
+		{opts: []Option{WithLenientBools(), WithBoolValues([]string{"ja"}, []string{"nein"})}, src: "nein", want: false},
+		{opts: []Option{WithLenientBools(), WithBoolValues(nil, []string{"y"})}, src: "y", want: false},
+	}
+	for _, tt := range tests {
+		sc := newScanner(tt.opts)
+		var got bool
+		err := sc.convertAssign(&got, tt.src)
+		errstr := ""
+		if err != nil {
+			errstr = err.Error()
+		}
+		if errstr != tt.wanterr {
+			t.Errorf("convertAssign(*bool, %#v): got error %q, want %q", tt.src, errstr, tt.wanterr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("convertAssign(*bool, %#v): got %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+type flag bool
+
+func TestConvertBoolDestinations(t *testing.T) {
+	sc := newScanner([]Option{WithLenientBools()})
+
+	var nb sql.NullBool
+	if err := sc.nullable(&nb).Scan([]byte{1}); err != nil || nb != (sql.NullBool{Bool: true, Valid: true}) {
+		t.Errorf("BIT(1) into sql.NullBool: got %v, %v", nb, err)
+	}
+	if err := sc.nullable(&nb).Scan("no"); err != nil || nb != (sql.NullBool{Bool: false, Valid: true}) {
+		t.Errorf("word into sql.NullBool: got %v, %v", nb, err)
+	}
+	if err := sc.nullable(&nb).Scan(nil); err != nil || nb.Valid {
+		t.Errorf("NULL into sql.NullBool: got %v, %v", nb, err)
+	}
+
+	var n sql.Null[bool]
+	if err := sc.nullable(&n).Scan("Y"); err != nil || !n.V || !n.Valid {
+		t.Errorf("word into sql.Null[bool]: got %v, %v", n, err)
+	}
+	if err := sc.nullable(&n).Scan(nil); err != nil || n.Valid {
+		t.Errorf("NULL into sql.Null[bool]: got %v, %v", n, err)
+	}
+
+	var f flag
+	for _, src := range []any{[]byte{1}, "on", int64(1), true} {
+		f = false
+		if err := sc.convertAssign(&f, src); err != nil || !f {
+			t.Errorf("%#v into named bool: got %v, %v", src, f, err)
+		}
+	}
+	err := sc.convertAssign(&f, "maybe")
+	if want := `sql/driver: couldn't convert "maybe" into type bool`; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,763 @@
+// Command scangen generates scanners that let github.com/goapt/scan fill
+// structs without reflection.
+//
+// Add a directive next to the types of a package and run go generate:
+//
+//	//go:generate go run github.com/goapt/scan/cmd/scangen -type User,Order
+//
+// For each type scangen writes a function mapping a column to a field, by the
+// default rules of the scan package, and a function preparing the r.Scan
+// destinations of a query, and registers them with scan.RegisterScanner.
+// Columns are mapped once per query and each row is scanned straight into
+// its fields. The generated code is only used when no option changes the
+// mapping rules; fields with tag options such as unix or split are still
+// converted through reflection.
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const header = "// Code generated by scangen; DO NOT EDIT."
+
+func main() {
+	typeNames := flag.String("type", "", "comma separated list of type names; defaults to every struct with a db tag")
+	output := flag.String("output", "scan_gen.go", "output file name")
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	var types []string
+	if *typeNames != "" {
+		types = strings.Split(*typeNames, ",")
+	}
+
+	src, err := generate(dir, *output, types)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "scangen:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(filepath.Join(dir, *output), src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "scangen:", err)
+		os.Exit(1)
+	}
+}
+
+// generate returns the source of the scanners for names in the package in
+// dir. With no names, it generates them for the structs with a db tag that
+// it supports.
+func generate(dir, output string, names []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	files, structs, order, err := parseDir(fset, dir, output)
+	if err != nil {
+		return nil, err
+	}
+	explicit := names != nil
+	if !explicit {
+		for _, name := range order {
+			if hasDBTag(structs[name], structs) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	// Errors are ignored so that a package using the code scangen is about
+	// to write still type checks well enough.
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil), Error: func(error) {}}
+	pkg, _ := conf.Check(files[0].Name.Name, fset, files, nil)
+
+	g := &generator{pkg: pkg, imports: map[string]string{scanPath: "scan"}, tags: make(map[*types.Var]string)}
+	var body bytes.Buffer
+	var generated []string
+	for _, name := range names {
+		if _, ok := structs[name]; !ok {
+			return nil, fmt.Errorf("struct type %s not found in %s", name, dir)
+		}
+		p, err := g.plan(name)
+		if err != nil {
+			if explicit {
+				return nil, err
+			}
+			continue
+		}
+		g.write(&body, p)
+		generated = append(generated, name)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s\n\npackage %s\n\nimport (\n", header, pkg.Name())
+	// standard library packages first, as goimports groups them
+	paths := slices.Sorted(maps.Keys(g.imports))
+	slices.SortStableFunc(paths, func(a, b string) int {
+		if thirdParty(a) == thirdParty(b) {
+			return 0
+		}
+		if thirdParty(a) {
+			return 1
+		}
+		return -1
+	})
+	for i, path := range paths {
+		if i > 0 && thirdParty(path) != thirdParty(paths[i-1]) {
+			buf.WriteString("\n")
+		}
+		if name := g.imports[path]; name != pathName(path) {
+			fmt.Fprintf(&buf, "\t%s %q\n", name, path)
+		} else {
+			fmt.Fprintf(&buf, "\t%q\n", path)
+		}
+	}
+	buf.WriteString(")\n\nfunc init() {\n")
+	for _, name := range generated {
+		fmt.Fprintf(&buf, "\tscan.RegisterScanner(prepare%s)\n", exported(name))
+	}
+	buf.WriteString("}\n")
+	buf.Write(body.Bytes())
+
+	return format.Source(buf.Bytes())
+}
+
+const scanPath = "github.com/goapt/scan"
+
+// parseDir returns the files of the package in dir and its non-generic
+// struct types, with their names in declaration order.
+func parseDir(fset *token.FileSet, dir, output string) ([]*ast.File, map[string]*ast.StructType, []string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var files []*ast.File
+	structs := make(map[string]*ast.StructType)
+	var order []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == output {
+			continue
+		}
+		if ok, err := build.Default.MatchFile(dir, name); err != nil || !ok {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		files = append(files, f)
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok || ts.TypeParams != nil || ts.Assign.IsValid() {
+					continue
+				}
+				structs[ts.Name.Name] = st
+				order = append(order, ts.Name.Name)
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, nil, nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	return files, structs, order, nil
+}
+
+// structOf returns the struct type of expr when it is declared in the package.
+func structOf(expr ast.Expr, structs map[string]*ast.StructType) *ast.StructType {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return structs[t.Name]
+	case *ast.StructType:
+		return t
+	case *ast.ParenExpr:
+		return structOf(t.X, structs)
+	}
+	return nil
+}
+
+// hasDBTag reports whether st or a struct nested in it has a db tag.
+func hasDBTag(st *ast.StructType, structs map[string]*ast.StructType) bool {
+	for _, f := range st.Fields.List {
+		if f.Tag != nil && strings.Contains(f.Tag.Value, `db:"`) {
+			return true
+		}
+		if nested := structOf(f.Type, structs); nested != nil && hasDBTag(nested, structs) {
+			return true
+		}
+	}
+	return false
+}
+
+// A target is a field columns are scanned into, given by the fields on the
+// way to it from the generated struct.
+type target struct {
+	path []*types.Var
+	// tag is the db tag of the field when it has options, otherwise "".
+	tag string
+	// settable is false for fields reflection could not set either; their
+	// columns are handled like unmapped ones.
+	settable bool
+}
+
+// plan holds how the columns of a query map to the fields of a struct type,
+// following newStructMapping of the scan package with its default options.
+type plan struct {
+	name    string
+	typ     *types.Named
+	targets []target
+	// positions, occurrences and tags map the `db:"@N"`, `db:"name#N"` and
+	// other tag names to targets; names maps field names, as returned by
+	// scan.ScannerMapper, to targets.
+	positions   map[int]int
+	occurrences map[occurrence]int
+	tags        map[string]int
+	names       map[string]int
+	extra       []*types.Var
+}
+
+type occurrence struct {
+	name string
+	n    int
+}
+
+type generator struct {
+	pkg *types.Package
+	// imports maps the paths imported by the generated code to their names.
+	imports map[string]string
+	// tags holds the struct tags of the fields seen so far.
+	tags map[*types.Var]string
+}
+
+// plan maps the columns of the struct type name to its fields.
+func (g *generator) plan(name string) (*plan, error) {
+	obj, ok := g.pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("struct type %s not found", name)
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a defined struct type", name)
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a struct type", name)
+	}
+	methods := types.NewMethodSet(types.NewPointer(named))
+	for _, m := range []string{"ScanDest", "ScanColumn"} {
+		if methods.Lookup(g.pkg, m) != nil {
+			return nil, fmt.Errorf("%s implements %s itself", name, m)
+		}
+	}
+
+	p := &plan{
+		name:        name,
+		typ:         named,
+		positions:   make(map[int]int),
+		occurrences: make(map[occurrence]int),
+		tags:        make(map[string]int),
+		names:       make(map[string]int),
+	}
+	tagged := make(map[string][]*types.Var)
+	var tagOrder []string
+	g.collectTags(st, nil, nil, tagged, &tagOrder, &p.extra)
+	if p.extra != nil {
+		if err := g.checkExtra(p); err != nil {
+			return nil, err
+		}
+	}
+	for _, tag := range tagOrder {
+		path := tagged[tag]
+		id, err := g.add(p, path)
+		if err != nil {
+			return nil, err
+		}
+		if n, ok := strings.CutPrefix(tag, "@"); ok && isOrdinal(n) {
+			i, _ := strconv.Atoi(n)
+			p.positions[i-1] = id
+		}
+		if j := strings.LastIndexByte(tag, '#'); j >= 0 && isOrdinal(tag[j+1:]) {
+			n, _ := strconv.Atoi(tag[j+1:])
+			p.occurrences[occurrence{tag[:j], n}] = id
+		}
+		p.tags[tag] = id
+	}
+	for _, name := range g.promotedNames(named) {
+		path, ok := g.fieldByName(named, name)
+		if !ok || g.ignoredPath(path) || slices.Equal(path, p.extra) {
+			continue
+		}
+		id, err := g.add(p, path)
+		if err != nil {
+			return nil, err
+		}
+		if p.targets[id].settable {
+			p.names[name] = id
+		}
+	}
+	return p, nil
+}
+
+// add returns the target of path, adding it to p when new.
+func (g *generator) add(p *plan, path []*types.Var) (int, error) {
+	for id, t := range p.targets {
+		if slices.Equal(t.path, path) {
+			return id, nil
+		}
+	}
+	t := target{path: path, settable: settable(path)}
+	if t.settable {
+		last := path[len(path)-1]
+		if !g.expressible(last.Type()) {
+			return 0, fmt.Errorf("%s: the type of field %s cannot be named in package %s", p.name, selector(path), g.pkg.Name())
+		}
+		for i, f := range path[:len(path)-1] {
+			if _, ok := f.Type().(*types.Pointer); ok && !settable(path[:i+1]) {
+				return 0, fmt.Errorf("%s: field %s is reached through the unexported embedded pointer %s", p.name, selector(path), selector(path[:i+1]))
+			}
+		}
+		if tag, _ := g.dbTag(last); strings.Contains(tag, ",") {
+			t.tag = tag
+		}
+	}
+	p.targets = append(p.targets, t)
+	return len(p.targets) - 1, nil
+}
+
+// checkExtra checks that the extra field of p is a map with string keys that
+// the generated code can set.
+func (g *generator) checkExtra(p *plan) error {
+	f := p.extra[len(p.extra)-1]
+	m, ok := f.Type().Underlying().(*types.Map)
+	if !ok {
+		return fmt.Errorf("%s: extra field %s must be a map with string keys", p.name, selector(p.extra))
+	}
+	if k, ok := m.Key().Underlying().(*types.Basic); !ok || k.Kind() != types.String {
+		return fmt.Errorf("%s: extra field %s must be a map with string keys", p.name, selector(p.extra))
+	}
+	if !settable(p.extra) || !g.expressible(f.Type()) {
+		return fmt.Errorf("%s: extra field %s cannot be set by generated code", p.name, selector(p.extra))
+	}
+	return nil
+}
+
+// collectTags records the tagged fields of st like initFieldTag of the scan
+// package: nested structs and embedded struct pointers first, the last field
+// with a name winning.
+func (g *generator) collectTags(st *types.Struct, parents []*types.Struct, path []*types.Var, tagged map[string][]*types.Var, order *[]string, extra *[]*types.Var) {
+	parents = append(parents, st)
+	for _, f := range g.fields(st) {
+		tag, ok := g.dbTag(f)
+		if ok && tag == "-" {
+			continue
+		}
+		fieldPath := append(slices.Clip(path), f)
+		ft := f.Type()
+		if p, isPointer := ft.(*types.Pointer); isPointer && f.Embedded() {
+			ft = p.Elem()
+		}
+		if nested, isStruct := ft.Underlying().(*types.Struct); isStruct && !slices.Contains(parents, nested) {
+			g.collectTags(nested, parents, fieldPath, tagged, order, extra)
+		}
+		if !ok {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if hasExtra(opts) {
+			*extra = fieldPath
+			continue
+		}
+		if name != "" {
+			if _, seen := tagged[name]; !seen {
+				*order = append(*order, name)
+			}
+			tagged[name] = fieldPath
+		}
+	}
+}
+
+// hasExtra reports whether the tag options opts include extra. It splits them
+// like parseTag of the scan package, which lets split take a comma.
+func hasExtra(opts string) bool {
+	for opts != "" {
+		if quoted, ok := strings.CutPrefix(opts, "split='"); ok {
+			if _, rest, ok := strings.Cut(quoted, "'"); ok {
+				opts = strings.TrimPrefix(rest, ",")
+				continue
+			}
+		}
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		switch key, value, _ := strings.Cut(opt, "="); key {
+		case "extra":
+			return true
+		case "split":
+			if value == "" {
+				opts = strings.TrimPrefix(opts, ",")
+			}
+		}
+	}
+	return false
+}
+
+// isOrdinal reports whether s is a positive number formatted by strconv.Itoa,
+// the only form column ordinals and occurrences are looked up in.
+func isOrdinal(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n > 0 && strconv.Itoa(n) == s
+}
+
+// dbTag returns the db tag of the struct field f.
+func (g *generator) dbTag(f *types.Var) (string, bool) {
+	return reflect.StructTag(g.tags[f]).Lookup("db")
+}
+
+// fields returns the fields of st, recording their tags.
+func (g *generator) fields(st *types.Struct) []*types.Var {
+	fields := make([]*types.Var, st.NumFields())
+	for i := range fields {
+		fields[i] = st.Field(i)
+		g.tags[fields[i]] = st.Tag(i)
+	}
+	return fields
+}
+
+// ignoredPath reports whether a field on path is tagged `db:"-"`.
+func (g *generator) ignoredPath(path []*types.Var) bool {
+	for _, f := range path {
+		if tag, ok := g.dbTag(f); ok && tag == "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// settable reports whether reflection could set the field at the end of path,
+// which takes exported fields, reached only through exported or embedded ones.
+func settable(path []*types.Var) bool {
+	for _, f := range path {
+		if !f.Exported() && !f.Embedded() {
+			return false
+		}
+	}
+	return path[len(path)-1].Exported()
+}
+
+// embeddedStruct returns the struct type an embedded field of type t
+// promotes the fields of, if any.
+func embeddedStruct(t types.Type) (types.Type, bool) {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	_, ok := t.Underlying().(*types.Struct)
+	return t, ok
+}
+
+// promotedNames returns the names of the fields of typ and of the structs it
+// embeds, at any depth, in the order they are found.
+func (g *generator) promotedNames(typ types.Type) []string {
+	var names []string
+	seen := make(map[string]bool)
+	visited := make(map[types.Type]bool)
+	next := []types.Type{typ}
+	for len(next) > 0 {
+		t := next[0]
+		next = next[1:]
+		if visited[t] {
+			continue
+		}
+		visited[t] = true
+		for _, f := range g.fields(t.Underlying().(*types.Struct)) {
+			if !seen[f.Name()] {
+				seen[f.Name()] = true
+				names = append(names, f.Name())
+			}
+			if f.Embedded() {
+				if et, ok := embeddedStruct(f.Type()); ok {
+					next = append(next, et)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// fieldByName returns the path of the field name in typ, following
+// reflect.Type.FieldByName: the shallowest field wins, and fields of the same
+// depth annihilate each other.
+func (g *generator) fieldByName(typ types.Type, name string) ([]*types.Var, bool) {
+	type fieldScan struct {
+		typ  types.Type
+		path []*types.Var
+	}
+	var current []fieldScan
+	next := []fieldScan{{typ: typ}}
+	var nextCount map[types.Type]int
+	visited := make(map[types.Type]bool)
+	var result []*types.Var
+	ok := false
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count := nextCount
+		nextCount = nil
+		for _, scan := range current {
+			t := scan.typ
+			if visited[t] {
+				continue
+			}
+			visited[t] = true
+			for _, f := range g.fields(t.Underlying().(*types.Struct)) {
+				if f.Name() == name {
+					if count[t] > 1 || ok {
+						return nil, false
+					}
+					result = append(slices.Clip(scan.path), f)
+					ok = true
+					continue
+				}
+				if ok || !f.Embedded() {
+					continue
+				}
+				et, isStruct := embeddedStruct(f.Type())
+				if !isStruct {
+					continue
+				}
+				if nextCount[et] > 0 {
+					nextCount[et] = 2
+					continue
+				}
+				if nextCount == nil {
+					nextCount = make(map[types.Type]int)
+				}
+				nextCount[et] = 1
+				if count[t] > 1 {
+					nextCount[et] = 2
+				}
+				next = append(next, fieldScan{typ: et, path: append(slices.Clip(scan.path), f)})
+			}
+		}
+		if ok {
+			break
+		}
+	}
+	return result, ok
+}
+
+// expressible reports whether the generated code can name t.
+func (g *generator) expressible(t types.Type) bool {
+	switch t := types.Unalias(t).(type) {
+	case *types.Named:
+		obj := t.Obj()
+		if obj.Pkg() != nil && obj.Pkg() != g.pkg && !obj.Exported() {
+			return false
+		}
+		for i := range t.TypeArgs().Len() {
+			if !g.expressible(t.TypeArgs().At(i)) {
+				return false
+			}
+		}
+		return true
+	case *types.Pointer:
+		return g.expressible(t.Elem())
+	case *types.Slice:
+		return g.expressible(t.Elem())
+	case *types.Array:
+		return g.expressible(t.Elem())
+	case *types.Chan:
+		return g.expressible(t.Elem())
+	case *types.Map:
+		return g.expressible(t.Key()) && g.expressible(t.Elem())
+	case *types.Struct:
+		for i := range t.NumFields() {
+			f := t.Field(i)
+			if !f.Exported() && f.Pkg() != g.pkg || !g.expressible(f.Type()) {
+				return false
+			}
+		}
+		return true
+	case *types.Signature:
+		for _, tuple := range []*types.Tuple{t.Params(), t.Results()} {
+			for i := range tuple.Len() {
+				if !g.expressible(tuple.At(i).Type()) {
+					return false
+				}
+			}
+		}
+		return true
+	case *types.Interface:
+		for i := range t.NumMethods() {
+			if m := t.Method(i); !m.Exported() && m.Pkg() != g.pkg {
+				return false
+			}
+		}
+		return true
+	}
+	return true
+}
+
+// typeString returns the source form of t in the generated file, importing
+// the packages it refers to.
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == g.pkg {
+			return ""
+		}
+		if name, ok := g.imports[p.Path()]; ok {
+			return name
+		}
+		name := p.Name()
+		for n := 2; slices.Contains(slices.Collect(maps.Values(g.imports)), name); n++ {
+			name = p.Name() + strconv.Itoa(n)
+		}
+		g.imports[p.Path()] = name
+		return name
+	})
+}
+
+// write writes the scanner of p to buf.
+func (g *generator) write(buf *bytes.Buffer, p *plan) {
+	typ := g.typeString(p.typ)
+	field := "fieldOf" + exported(p.name)
+
+	fmt.Fprintf(buf, "\n// %s returns the field of %s that the column at index i is\n", field, p.name)
+	fmt.Fprintf(buf, "// scanned into, or -1. col is its name and n counts the columns so named.\n")
+	fmt.Fprintf(buf, "func %s(i int, col string, n int) int {\n", field)
+	if len(p.positions) > 0 {
+		buf.WriteString("\tswitch i {\n")
+		for _, i := range slices.Sorted(maps.Keys(p.positions)) {
+			fmt.Fprintf(buf, "\tcase %d:\n\t\treturn %d\n", i, p.result(p.positions[i]))
+		}
+		buf.WriteString("\t}\n")
+	}
+	if len(p.occurrences) > 0 {
+		buf.WriteString("\tswitch {\n")
+		for _, o := range slices.SortedFunc(maps.Keys(p.occurrences), func(a, b occurrence) int {
+			return cmp.Or(strings.Compare(a.name, b.name), cmp.Compare(a.n, b.n))
+		}) {
+			fmt.Fprintf(buf, "\tcase n == %d && col == %q:\n\t\treturn %d\n", o.n, o.name, p.result(p.occurrences[o]))
+		}
+		buf.WriteString("\t}\n")
+	}
+	if len(p.tags) > 0 {
+		buf.WriteString("\tswitch col {\n")
+		for _, tag := range slices.Sorted(maps.Keys(p.tags)) {
+			fmt.Fprintf(buf, "\tcase %q:\n\t\treturn %d\n", tag, p.result(p.tags[tag]))
+		}
+		buf.WriteString("\t}\n")
+	}
+	if len(p.names) > 0 {
+		buf.WriteString("\tswitch scan.ScannerMapper(col) {\n")
+		for _, name := range slices.Sorted(maps.Keys(p.names)) {
+			fmt.Fprintf(buf, "\tcase %q:\n\t\treturn %d\n", name, p.names[name])
+		}
+		buf.WriteString("\t}\n")
+	}
+	buf.WriteString("\treturn -1\n}\n")
+
+	fmt.Fprintf(buf, "\n// prepare%s returns the r.Scan destinations of cols for %s and a\n", exported(p.name), p.name)
+	fmt.Fprintf(buf, "// function binding them to the fields of each row.\n")
+	fmt.Fprintf(buf, "func prepare%s(sc *scan.Scanner, cols []string) ([]any, func(*%s)) {\n", exported(p.name), typ)
+	fmt.Fprintf(buf, "\tdests := make([]any, len(cols))\n\tbinds := make([]func(*%s), 0, len(cols))\n", typ)
+	n := "0"
+	if len(p.occurrences) > 0 {
+		buf.WriteString("\tseen := make(map[string]int, len(cols))\n")
+		n = "seen[col]"
+	}
+	buf.WriteString("\tfor i, col := range cols {\n")
+	if len(p.occurrences) > 0 {
+		buf.WriteString("\t\tseen[col]++\n")
+	}
+	fmt.Fprintf(buf, "\t\tswitch %s(i, col, %s) {\n", field, n)
+	for id, t := range p.targets {
+		if !t.settable {
+			continue
+		}
+		last := t.path[len(t.path)-1]
+		fmt.Fprintf(buf, "\t\tcase %d:\n", id)
+		fmt.Fprintf(buf, "\t\t\td := scan.NewField[%s](sc, col, %q)\n", g.typeString(last.Type()), t.tag)
+		fmt.Fprintf(buf, "\t\t\tdests[i] = d\n")
+		g.writeBind(buf, typ, t.path)
+	}
+	buf.WriteString("\t\tdefault:\n")
+	if p.extra != nil {
+		fmt.Fprintf(buf, "\t\t\td := scan.NewExtra[%s](sc, col)\n", g.typeString(p.extra[len(p.extra)-1].Type()))
+		fmt.Fprintf(buf, "\t\t\tdests[i] = d\n")
+		g.writeBind(buf, typ, p.extra)
+	} else {
+		buf.WriteString("\t\t\tdests[i] = new(any)\n")
+	}
+	buf.WriteString("\t\t}\n\t}\n")
+	fmt.Fprintf(buf, "\treturn dests, func(v *%s) {\n\t\tfor _, bind := range binds {\n\t\t\tbind(v)\n\t\t}\n\t}\n}\n", typ)
+}
+
+// writeBind writes the function binding d to the field at the end of path,
+// allocating the nil embedded pointers on the way like the scan package.
+func (g *generator) writeBind(buf *bytes.Buffer, typ string, path []*types.Var) {
+	var allocs []string
+	for i, f := range path[:len(path)-1] {
+		if ptr, ok := f.Type().(*types.Pointer); ok {
+			sel := "v." + selector(path[:i+1])
+			allocs = append(allocs, fmt.Sprintf("if %s == nil {\n%s = new(%s)\n}\n", sel, sel, g.typeString(ptr.Elem())))
+		}
+	}
+	if len(allocs) == 0 {
+		fmt.Fprintf(buf, "\t\t\tbinds = append(binds, func(v *%s) { d.Bind(&v.%s) })\n", typ, selector(path))
+		return
+	}
+	fmt.Fprintf(buf, "\t\t\tbinds = append(binds, func(v *%s) {\n%s\td.Bind(&v.%s)\n})\n", typ, strings.Join(allocs, ""), selector(path))
+}
+
+// result returns what the field function returns for target id.
+func (p *plan) result(id int) int {
+	if !p.targets[id].settable {
+		return -1
+	}
+	return id
+}
+
+// selector returns the selector of the field at the end of path.
+func selector(path []*types.Var) string {
+	names := make([]string, len(path))
+	for i, f := range path {
+		names[i] = f.Name()
+	}
+	return strings.Join(names, ".")
+}
+
+// exported returns name with its first letter in upper case.
+func exported(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// thirdParty reports whether path is outside the standard library, whose
+// paths have no dot in their first element.
+func thirdParty(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return strings.Contains(first, ".")
+}
+
+// pathName returns the default name of the package imported as path.
+func pathName(path string) string {
+	return path[strings.LastIndexByte(path, '/')+1:]
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestGenerateMatchesCheckedInOutput(t *testing.T) {
+	const dir = "../../internal/scangentest"
+	want, err := os.ReadFile(dir + "/scan_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := generate(dir, "scan_gen.go", []string{"User", "Order", "Report", "Join"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("generated output differs from %s/scan_gen.go, run go generate:\n%s", dir, got)
+	}
+}
+
+func TestGenerateDefaultsToTaggedStructs(t *testing.T) {
+	src, err := generate("../../internal/scangentest", "scan_gen.go", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"*User", "*Order", "*Report"} {
+		if !bytes.Contains(src, []byte(want)) {
+			t.Errorf("generated output has no scanner for %s", want)
+		}
+	}
+	if !bytes.Contains(src, []byte("*Address")) {
+		t.Errorf("generated output has no scanner for tagged type Address")
+	}
+	if bytes.Contains(src, []byte("*audit")) {
+		t.Errorf("generated output has a scanner for untagged type audit")
+	}
+}
+
+func TestGenerateUnknownType(t *testing.T) {
+	if _, err := generate("../../internal/scangentest", "scan_gen.go", []string{"Missing"}); err == nil {
+		t.Fatal("expected error for unknown type")
+	}
+}
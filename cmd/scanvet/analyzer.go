@@ -0,0 +1,269 @@
+package main
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goapt/scan"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const scanPath = "github.com/goapt/scan"
+
+// Analyzer reports columns of literal SELECT queries that scan.Rows or
+// scan.Row cannot map to a field of the destination type.
+var Analyzer = &analysis.Analyzer{
+	Name:     "scanvet",
+	Doc:      "check that the columns of literal SELECT queries map to the fields scanned by scan.Rows and scan.Row",
+	URL:      "https://pkg.go.dev/github.com/goapt/scan/cmd/scanvet",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	queries := rowsQueries(pass, insp)
+
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		typ, ok := scanTarget(pass, call)
+		// Options such as WithMapper or WithTagSources change how columns
+		// map to fields, which cannot be known statically.
+		if !ok || len(call.Args) != 1 {
+			return
+		}
+		id, ok := ast.Unparen(call.Args[0]).(*ast.Ident)
+		if !ok {
+			return
+		}
+		query, ok := queries[pass.TypesInfo.Uses[id]]
+		if !ok {
+			return
+		}
+		cols, ok := selectColumns(query)
+		if !ok {
+			return
+		}
+		m := newMapping(pass.Pkg, typ)
+		if m == nil {
+			return
+		}
+		occurrences := make(map[string]int, len(cols))
+		for i, col := range cols {
+			occurrences[col]++
+			if col != "" && !m.maps(i, col, occurrences[col]) {
+				pass.Reportf(call.Pos(), "column %q does not map to a field of %s", col, types.TypeString(typ, types.RelativeTo(pass.Pkg)))
+			}
+		}
+	})
+	return nil, nil
+}
+
+// scanTarget returns T of a call to scan.Rows[T] or scan.Row[T].
+func scanTarget(pass *analysis.Pass, call *ast.CallExpr) (types.Type, bool) {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != scanPath || fn.Name() != "Rows" && fn.Name() != "Row" {
+		return nil, false
+	}
+	var id *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.IndexExpr:
+		switch x := fun.X.(type) {
+		case *ast.SelectorExpr:
+			id = x.Sel
+		case *ast.Ident:
+			id = x
+		}
+	}
+	inst, ok := pass.TypesInfo.Instances[id]
+	if id == nil || !ok || inst.TypeArgs.Len() != 1 {
+		return nil, false
+	}
+	return inst.TypeArgs.At(0), true
+}
+
+// rowsQueries returns the SQL of the variables assigned exactly once, from a
+// Query or QueryContext call with a constant query string.
+func rowsQueries(pass *analysis.Pass, insp *inspector.Inspector) map[types.Object]string {
+	queries := make(map[types.Object]string)
+	assigned := make(map[types.Object]int)
+	record := func(lhs ast.Expr, rhs []ast.Expr) {
+		id, ok := lhs.(*ast.Ident)
+		if !ok {
+			return
+		}
+		obj := pass.TypesInfo.ObjectOf(id)
+		if obj == nil {
+			return
+		}
+		assigned[obj]++
+		if len(rhs) != 1 {
+			return
+		}
+		if query, ok := queryString(pass, rhs[0]); ok {
+			queries[obj] = query
+		}
+	}
+
+	insp.Preorder([]ast.Node{(*ast.AssignStmt)(nil), (*ast.ValueSpec)(nil)}, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				if i == 0 {
+					record(lhs, n.Rhs)
+				} else {
+					record(lhs, nil)
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				if i == 0 {
+					record(name, n.Values)
+				} else {
+					record(name, nil)
+				}
+			}
+		}
+	})
+	for obj, n := range assigned {
+		if n > 1 {
+			delete(queries, obj)
+		}
+	}
+	return queries
+}
+
+// queryString returns the constant query passed to a Query or QueryContext call.
+func queryString(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return "", false
+	}
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	arg := 0
+	switch sel.Sel.Name {
+	case "Query":
+	case "QueryContext":
+		arg = 1
+	default:
+		return "", false
+	}
+	if len(call.Args) <= arg {
+		return "", false
+	}
+	tv := pass.TypesInfo.Types[call.Args[arg]]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// mapping mirrors the rules scan uses to map columns to struct fields.
+type mapping struct {
+	pkg *types.Package
+	typ types.Type
+	// tagged records whether the field tagged with a column name can be set.
+	tagged map[string]bool
+	extra  bool
+}
+
+// newMapping returns the mapping of typ, or nil when scan does not map its
+// columns to fields by name.
+func newMapping(pkg *types.Package, typ types.Type) *mapping {
+	for {
+		p, ok := typ.Underlying().(*types.Pointer)
+		if !ok {
+			break
+		}
+		typ = p.Elem()
+	}
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	mset := types.NewMethodSet(types.NewPointer(typ))
+	for _, name := range []string{"ScanDest", "ScanColumn"} {
+		if mset.Lookup(nil, name) != nil {
+			return nil
+		}
+	}
+	m := &mapping{pkg: pkg, typ: typ, tagged: make(map[string]bool)}
+	m.initTags(st, nil, true)
+	return m
+}
+
+// initTags records the tagged fields of st like initFieldTag of the scan
+// package, descending into nested structs and embedded struct pointers.
+// parents lists the structs being traversed.
+func (m *mapping) initTags(st *types.Struct, parents []*types.Struct, settable bool) {
+	parents = append(parents, st)
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		tag, ok := reflect.StructTag(st.Tag(i)).Lookup("db")
+		if tag == "-" {
+			continue
+		}
+		ft := f.Type()
+		if p, isPointer := ft.(*types.Pointer); isPointer && f.Embedded() {
+			ft = p.Elem()
+		}
+		if nested, isStruct := ft.Underlying().(*types.Struct); isStruct && !slices.Contains(parents, nested) {
+			m.initTags(nested, parents, settable && (f.Exported() || f.Embedded()))
+		}
+		if !ok {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		for _, opt := range strings.Split(opts, ",") {
+			if opt == "extra" {
+				m.extra = true
+			}
+		}
+		if name != "" {
+			m.tagged[name] = settable && f.Exported()
+		}
+	}
+}
+
+// maps reports whether scan stores col, the column at index i and the
+// occurrence n of its name, in a field of the struct.
+func (m *mapping) maps(i int, col string, n int) bool {
+	if m.extra {
+		return true
+	}
+	for _, name := range []string{"@" + strconv.Itoa(i+1), col + "#" + strconv.Itoa(n), col} {
+		if settable, ok := m.tagged[name]; ok {
+			return settable
+		}
+	}
+	obj, index, _ := types.LookupFieldOrMethod(m.typ, false, m.pkg, scan.ScannerMapper(col))
+	f, ok := obj.(*types.Var)
+	if !ok || !f.IsField() {
+		return false
+	}
+	// every field on the path must be exported or embedded, and not ignored
+	t := m.typ
+	for _, i := range index {
+		if p, ok := t.Underlying().(*types.Pointer); ok {
+			t = p.Elem()
+		}
+		st := t.Underlying().(*types.Struct)
+		sf := st.Field(i)
+		if !sf.Exported() && !sf.Embedded() || reflect.StructTag(st.Tag(i)).Get("db") == "-" {
+			return false
+		}
+		t = sf.Type()
+	}
+	return f.Exported()
+}
@@ -0,0 +1,20 @@
+// Command scanvet checks that the columns of literal SELECT queries map to
+// the fields scanned by scan.Rows and scan.Row.
+//
+// It follows the rows passed to scan.Rows[T] or scan.Row[T] back to a Query
+// or QueryContext call with a constant query string, reads the names of the
+// selected columns and reports those that the default ScannerMapper and the
+// db tags of T leave unmapped, which would otherwise be dropped silently.
+// Calls passing options are skipped, as options such as scan.WithMapper
+// change the mapping in ways that are only known at run time.
+//
+// Run it on its own or through go vet:
+//
+//	go vet -vettool=$(which scanvet) ./...
+package main
+
+import "golang.org/x/tools/go/analysis/singlechecker"
+
+func main() {
+	singlechecker.Main(Analyzer)
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokWord   tokenKind = iota // keyword, identifier or number
+	tokQuoted                  // `quoted` or "quoted" identifier
+	tokString                  // 'string' literal
+	tokPunct                   // any other character
+)
+
+type sqlToken struct {
+	kind  tokenKind
+	text  string
+	depth int
+}
+
+func (t sqlToken) is(word string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, word)
+}
+
+func (t sqlToken) ident() bool {
+	return t.kind == tokQuoted || t.kind == tokWord && !unicode.IsDigit(rune(t.text[0]))
+}
+
+// tokenize splits query into tokens, dropping comments and recording the
+// parenthesis depth of each token.
+func tokenize(query string) []sqlToken {
+	var toks []sqlToken
+	depth := 0
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '#' || c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return toks
+			}
+			i += end + 1
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return toks
+			}
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`':
+			text, n := quoted(query[i:])
+			kind := tokQuoted
+			if c == '\'' {
+				kind = tokString
+			}
+			toks = append(toks, sqlToken{kind: kind, text: text, depth: depth})
+			i += n
+		case isWordByte(c):
+			j := i
+			for j < len(query) && isWordByte(query[j]) {
+				j++
+			}
+			toks = append(toks, sqlToken{kind: tokWord, text: query[i:j], depth: depth})
+			i = j
+		default:
+			if c == ')' && depth > 0 {
+				depth--
+			}
+			toks = append(toks, sqlToken{kind: tokPunct, text: string(c), depth: depth})
+			if c == '(' {
+				depth++
+			}
+			i++
+		}
+	}
+	return toks
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// quoted returns the unquoted text of the quoted token at the start of s and
+// the length of the token. A doubled quote stands for the quote itself.
+func quoted(s string) (string, int) {
+	q := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && q != '`' && i+1 < len(s):
+			i++
+			b.WriteByte(s[i])
+		case s[i] == q && i+1 < len(s) && s[i+1] == q:
+			i++
+			b.WriteByte(q)
+		case s[i] == q:
+			return b.String(), i + 1
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), len(s)
+}
+
+// selectEnd lists the keywords ending the select list of a query.
+var selectEnd = []string{"FROM", "INTO", "WHERE", "GROUP", "HAVING", "WINDOW", "ORDER", "LIMIT", "UNION", "EXCEPT", "INTERSECT", "FOR", "LOCK"}
+
+// selectModifiers lists the keywords that may precede the select list.
+var selectModifiers = []string{"ALL", "DISTINCT", "DISTINCTROW", "HIGH_PRIORITY", "STRAIGHT_JOIN", "SQL_SMALL_RESULT", "SQL_BIG_RESULT", "SQL_BUFFER_RESULT", "SQL_NO_CACHE", "SQL_CALC_FOUND_ROWS"}
+
+// selectColumns returns the names of the columns returned by the first
+// top-level SELECT in query. A name is empty when the column is an
+// expression without an alias, whose name is chosen by the server.
+// It reports false when query is not a SELECT or selects *.
+func selectColumns(query string) ([]string, bool) {
+	toks := tokenize(query)
+	start := -1
+	for i, t := range toks {
+		if t.depth == 0 && t.is("SELECT") {
+			start = i + 1
+			break
+		}
+	}
+	if start < 0 {
+		return nil, false
+	}
+	for start < len(toks) && isOneOf(toks[start], selectModifiers) {
+		start++
+	}
+
+	var cols []string
+	var item []sqlToken
+	for i := start; ; i++ {
+		end := i == len(toks) || toks[i].depth == 0 && (toks[i].text == ";" || isOneOf(toks[i], selectEnd))
+		if end || toks[i].depth == 0 && toks[i].text == "," {
+			name, ok := columnName(item)
+			if !ok {
+				return nil, false
+			}
+			cols = append(cols, name)
+			item = item[:0]
+			if end {
+				return cols, true
+			}
+			continue
+		}
+		item = append(item, toks[i])
+	}
+}
+
+// columnName returns the name of the column selected by item.
+func columnName(item []sqlToken) (string, bool) {
+	n := len(item)
+	if n == 0 || item[n-1].text == "*" {
+		return "", false
+	}
+	last := item[n-1]
+	if n >= 2 && item[n-2].depth == 0 && item[n-2].is("AS") {
+		return last.text, true
+	}
+	if isColumnRef(item) {
+		return last.text, true
+	}
+	// an implicit alias follows an expression without AS
+	if n >= 2 && last.ident() && !isOneOf(last, []string{"END", "NULL", "TRUE", "FALSE", "UNKNOWN"}) {
+		prev := item[n-2]
+		if prev.text == ")" || prev.kind != tokPunct {
+			return last.text, true
+		}
+	}
+	return "", true
+}
+
+// isColumnRef reports whether item is a possibly qualified column name.
+func isColumnRef(item []sqlToken) bool {
+	for i, t := range item {
+		if i%2 == 0 && !t.ident() || i%2 == 1 && t.text != "." {
+			return false
+		}
+	}
+	return len(item)%2 == 1
+}
+
+func isOneOf(t sqlToken, words []string) bool {
+	for _, w := range words {
+		if t.is(w) {
+			return true
+		}
+	}
+	return false
+}
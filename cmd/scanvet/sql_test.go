@@ -0,0 +1,40 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSelectColumns(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+		ok    bool
+	}{
+		{"SELECT id, name FROM users", []string{"id", "name"}, true},
+		{"select u.id, u.first_name AS name from users u", []string{"id", "name"}, true},
+		{"SELECT `order`.`id`, \"full name\" FROM `order`", []string{"id", "full name"}, true},
+		{"SELECT COUNT(*) AS total, MAX(age) oldest FROM users", []string{"total", "oldest"}, true},
+		{"SELECT COUNT(*) FROM users", []string{""}, true},
+		{"SELECT DISTINCT name FROM users WHERE id IN (SELECT id FROM admins)", []string{"name"}, true},
+		{"SELECT CASE WHEN a THEN 1 ELSE 0 END FROM t", []string{""}, true},
+		{"SELECT CASE WHEN a THEN 1 ELSE 0 END flag FROM t", []string{"flag"}, true},
+		{"SELECT CONCAT(first, ',', last) AS 'name' FROM t", []string{"name"}, true},
+		{"SELECT ? AS first_name", []string{"first_name"}, true},
+		{"SELECT 1 AS a UNION SELECT 2", []string{"a"}, true},
+		{"-- list users\nSELECT /* all */ id FROM users", []string{"id"}, true},
+		{"WITH t AS (SELECT 1 AS x) SELECT x AS y FROM t", []string{"y"}, true},
+		{"SELECT id FROM t;", []string{"id"}, true},
+		{"SELECT * FROM users", nil, false},
+		{"SELECT u.*, 1 AS x FROM users u", nil, false},
+		{"UPDATE users SET name = ?", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, ok := selectColumns(tt.query)
+			if ok != tt.ok || !slices.Equal(got, tt.want) {
+				t.Errorf("selectColumns() = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package a
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/goapt/scan"
+)
+
+type Base struct {
+	ID int64 `db:"id"`
+}
+
+type User struct {
+	Base
+	FirstName string
+	Email     string `db:"email_address"`
+	hidden    string `db:"hidden"`
+	Password  string `db:"-"`
+	Profile   struct {
+		Bio string `db:"bio"`
+	}
+}
+
+type Report struct {
+	Title  string
+	Extras map[string]any `db:",extra"`
+}
+
+type Join struct {
+	UserID  int64  `db:"id"`
+	OrderID int64  `db:"id#2"`
+	Status  string `db:"@3"`
+}
+
+// Account embeds a pointer, whose tagged fields are mapped too.
+type Account struct {
+	*Base
+	Name string
+}
+
+type Attributes map[string]string
+
+func (a *Attributes) ScanColumn(name string, src any) error { return nil }
+
+const userQuery = "SELECT id, first_name, email_address, bio FROM users"
+
+func ok(db *sql.DB) {
+	rows, _ := db.Query(userQuery)
+	scan.Rows[User](rows)
+
+	rows2, _ := db.Query("SELECT u.id, u.first_name AS first_name, COUNT(*) FROM users u WHERE id = ?", 1)
+	scan.Row[User](rows2)
+
+	rows3, _ := db.Query("SELECT * FROM users")
+	scan.Rows[User](rows3)
+
+	rows4, _ := db.Query("SELECT title, anything FROM reports")
+	scan.Rows[Report](rows4)
+
+	rows5, _ := db.Query("SELECT name, value FROM attributes")
+	scan.Rows[Attributes](rows5)
+
+	rows6, _ := db.Query("SELECT name FROM users")
+	scan.Rows[string](rows6)
+
+	rows7, _ := db.Query("SELECT u.id, o.id, o.state FROM users u JOIN orders o ON o.user_id = u.id")
+	scan.Rows[Join](rows7)
+
+	rows8, _ := db.Query("SELECT id, name FROM accounts")
+	scan.Rows[Account](rows8)
+}
+
+func mismatched(ctx context.Context, db *sql.DB) {
+	rows, _ := db.QueryContext(ctx, "SELECT id, firstname, mail FROM users")
+	scan.Rows[User](rows) // want `column "firstname" does not map to a field of User` `column "mail" does not map to a field of User`
+
+	rows2, _ := db.Query("SELECT hidden FROM users")
+	scan.Row[User](rows2) // want `column "hidden" does not map to a field of User`
+
+	rows3, _ := db.Query("SELECT id, nickname FROM users")
+	scan.Rows[*User](rows3) // want `column "nickname" does not map to a field of \*User`
+
+	rows4, _ := db.Query("SELECT id, password FROM users")
+	scan.Rows[User](rows4) // want `column "password" does not map to a field of User`
+}
+
+func withOptions(db *sql.DB) {
+	rows, _ := db.Query("SELECT id, firstname, mail FROM users")
+	scan.Rows[User](rows, scan.WithMapper(scan.SnakeToGo))
+
+	rows2, _ := db.Query("SELECT ID, FIRST_NAME FROM users")
+	scan.Rows[User](rows2, scan.WithIgnoreCase())
+
+	rows3, _ := db.Query("SELECT users.id, users.hidden FROM users")
+	scan.Row[User](rows3, scan.WithTableQualifiers())
+
+	rows4, _ := db.Query("SELECT ident FROM users")
+	scan.Rows[User](rows4, scan.WithTagSources(scan.TagKey("json")))
+}
+
+func reassigned(db *sql.DB, query string) {
+	rows, _ := db.Query("SELECT nope FROM users")
+	rows, _ = db.Query(query)
+	scan.Rows[User](rows)
+}
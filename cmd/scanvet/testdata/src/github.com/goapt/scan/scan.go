@@ -0,0 +1,30 @@
+// Package scan stubs the API of github.com/goapt/scan checked by scanvet.
+package scan
+
+import (
+	"database/sql"
+	"reflect"
+)
+
+type Option func()
+
+type TagSource func(tag reflect.StructTag) (string, bool)
+
+func WithMapper(mapper func(string) string) Option { return nil }
+
+func WithIgnoreCase() Option { return nil }
+
+func WithTableQualifiers() Option { return nil }
+
+func WithTagSources(sources ...TagSource) Option { return nil }
+
+func TagKey(key string) TagSource { return nil }
+
+func SnakeToGo(s string) string { return s }
+
+func Rows[T any](r *sql.Rows, opts ...Option) ([]T, error) { return nil, nil }
+
+func Row[T any](r *sql.Rows, opts ...Option) (T, error) {
+	var zero T
+	return zero, nil
+}
@@ -0,0 +1,160 @@
+package scan
+
+import (
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]func([]byte) ([]byte, error){
+		"gzip":   gunzip,
+		"base64": decodeBase64,
+		"hex":    decodeHex,
+	}
+)
+
+// RegisterCodec makes a decoding function available to the codec tag option
+// under name. Codecs transform the raw column bytes before they are converted
+// into the field, e.g. `db:"ssn,codec=aes"`, and can be chained with "|" as in
+// `db:"doc,codec=base64|gzip"`, which are applied from left to right.
+//
+// The codecs "gzip", "base64" and "hex" are registered by default. The name
+// "json" is reserved: as the last codec of a chain, as in `codec=gzip|json`,
+// it unmarshals the decoded bytes into the field with encoding/json.
+// RegisterCodec replaces any codec previously registered under name.
+func RegisterCodec(name string, decode func([]byte) ([]byte, error)) {
+	if decode == nil {
+		panic("scan: RegisterCodec decode is nil")
+	}
+	if name == jsonCodec {
+		panic("scan: RegisterCodec name json is reserved")
+	}
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[name] = decode
+}
+
+// jsonCodec names the codec that unmarshals the decoded bytes as JSON into
+// the field. It can only be the last codec of a chain.
+const jsonCodec = "json"
+
+func lookupCodec(name string) func([]byte) ([]byte, error) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	return codecs[name]
+}
+
+// codecScanner decodes string and []byte sources with a chain of codecs
+// before handing the result to dest. NULL is passed through unchanged.
+// Errors from dest are redacted, as the decoded value may be sensitive.
+type codecScanner struct {
+	dest  sql.Scanner
+	names string
+	typ   reflect.Type
+}
+
+// newCodecScanner returns a codecScanner for the field value v. A trailing
+// json codec replaces dest with a jsonScanner for v.
+func newCodecScanner(dest sql.Scanner, v reflect.Value, names string) codecScanner {
+	if names == jsonCodec {
+		names, dest = "", jsonScanner{dest: v}
+	} else if rest, ok := strings.CutSuffix(names, "|"+jsonCodec); ok {
+		names, dest = rest, jsonScanner{dest: v}
+	}
+	return codecScanner{dest: dest, names: names, typ: v.Type()}
+}
+
+func (c codecScanner) Scan(src any) error {
+	var b []byte
+	switch s := src.(type) {
+	case nil:
+		return c.dest.Scan(nil)
+	case string:
+		b = []byte(s)
+	case []byte:
+		b = s
+	default:
+		return &ScanError{Value: src, Type: c.typ, Err: errors.New("codecs need a string or []byte value")}
+	}
+
+	if c.names != "" {
+		for _, name := range strings.Split(c.names, "|") {
+			if name == jsonCodec {
+				return &ScanError{Value: src, Type: c.typ, Err: errors.New(`codec "json" must be the last codec`)}
+			}
+			decode := lookupCodec(name)
+			if decode == nil {
+				return &ScanError{Value: src, Type: c.typ, Err: fmt.Errorf("unknown codec %q", name)}
+			}
+			var err error
+			if b, err = decode(b); err != nil {
+				return &ScanError{Value: src, Type: c.typ, Err: fmt.Errorf("codec %q: %w", name, err)}
+			}
+		}
+	}
+	if err := c.dest.Scan(b); err != nil {
+		return &ScanError{Value: src, Type: c.typ, Err: redactedError{err: err}}
+	}
+	return nil
+}
+
+// redactedError hides the message of an error about a decoded value, which
+// may quote it. The original error is still available through errors.As.
+type redactedError struct {
+	err error
+}
+
+func (e redactedError) Error() string {
+	return "decoded value cannot be converted (details redacted)"
+}
+
+func (e redactedError) Unwrap() error {
+	return e.err
+}
+
+// jsonScanner unmarshals the source bytes as JSON into the value dest. NULL
+// sets dest to its zero value.
+type jsonScanner struct {
+	dest reflect.Value
+}
+
+func (j jsonScanner) Scan(src any) error {
+	b, ok := src.([]byte)
+	if !ok {
+		j.dest.SetZero()
+		return nil
+	}
+	return json.Unmarshal(b, j.dest.Addr().Interface())
+}
+
+func gunzip(b []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func decodeBase64(b []byte) ([]byte, error) {
+	out := make([]byte, base64.StdEncoding.DecodedLen(len(b)))
+	n, err := base64.StdEncoding.Decode(out, b)
+	return out[:n], err
+}
+
+func decodeHex(b []byte) ([]byte, error) {
+	out := make([]byte, hex.DecodedLen(len(b)))
+	n, err := hex.Decode(out, b)
+	return out[:n], err
+}
@@ -0,0 +1,111 @@
+package scan
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func gzipped(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestCodecScanner(t *testing.T) {
+	RegisterCodec("reverse", func(b []byte) ([]byte, error) {
+		out := slices.Clone(b)
+		slices.Reverse(out)
+		return out, nil
+	})
+	RegisterCodec("fail", func([]byte) ([]byte, error) {
+		return nil, errors.New("bad key")
+	})
+
+	tests := []struct {
+		names   string
+		src     any
+		want    string
+		wanterr string
+	}{
+		{names: "gzip", src: gzipped(t, "hello"), want: "hello"},
+		{names: "base64|gzip", src: base64.StdEncoding.EncodeToString(gzipped(t, "hello")), want: "hello"},
+		{names: "hex|reverse", src: []byte("6f6c6c6568"), want: "hello"},
+		{names: "reverse", src: nil, want: ""},
+		{names: "nope", src: "x", wanterr: `converting driver.Value type string ("x") to a string: unknown codec "nope"`},
+		{names: "fail", src: "x", wanterr: `converting driver.Value type string ("x") to a string: codec "fail": bad key`},
+		{names: "hex", src: "zz", wanterr: `converting driver.Value type string ("zz") to a string: codec "hex": encoding/hex: invalid byte: U+007A 'z'`},
+		{names: "json|hex", src: "x", wanterr: `converting driver.Value type string ("x") to a string: codec "json" must be the last codec`},
+		{names: "hex|json", src: "2268656c6c6f22", want: "hello"},
+		{names: "hex|json", src: "6869", wanterr: `converting driver.Value type string ("6869") to a string: decoded value cannot be converted (details redacted)`},
+		{names: "hex", src: int64(1), wanterr: `converting driver.Value type int64 ("1") to a string: codecs need a string or []byte value`},
+	}
+	for _, tt := range tests {
+		got := "unset"
+		err := defaultScanner.fieldDest(reflect.ValueOf(&got).Elem(), fieldTag{codec: tt.names}).Scan(tt.src)
+		errstr := ""
+		if err != nil {
+			errstr = err.Error()
+		}
+		if errstr != tt.wanterr {
+			t.Errorf("codec %q: got error %q, want %q", tt.names, errstr, tt.wanterr)
+			continue
+		}
+		if tt.wanterr != "" {
+			var se *ScanError
+			if !errors.As(err, &se) {
+				t.Errorf("codec %q: want *ScanError, got %T", tt.names, err)
+			}
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("codec %q: got %q, want %q", tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestCodecJSON(t *testing.T) {
+	type profile struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	var got profile
+	err := defaultScanner.fieldDest(reflect.ValueOf(&got).Elem(), fieldTag{codec: "gzip|json"}).Scan(gzipped(t, `{"name":"ann","tags":["a","b"]}`))
+	if err != nil || got.Name != "ann" || !slices.Equal(got.Tags, []string{"a", "b"}) {
+		t.Errorf("gzip|json: got %+v, %v", got, err)
+	}
+	if err := defaultScanner.fieldDest(reflect.ValueOf(&got).Elem(), fieldTag{codec: "json"}).Scan(nil); err != nil || got.Name != "" || got.Tags != nil {
+		t.Errorf("json NULL: got %+v, %v", got, err)
+	}
+}
+
+func TestCodecRedactsDecodedValue(t *testing.T) {
+	var got int
+	// the hex codec decodes to "123-45-6789", which is not an int
+	err := defaultScanner.fieldDest(reflect.ValueOf(&got).Elem(), fieldTag{codec: "hex"}).Scan([]byte("3132332d34352d36373839"))
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if strings.Contains(err.Error(), "123-45-6789") {
+		t.Errorf("error leaks the decoded value: %v", err)
+	}
+	want := `converting driver.Value type []uint8 ("3132332d34352d36373839") to a int: decoded value cannot be converted (details redacted)`
+	if err.Error() != want {
+		t.Errorf("got error %q, want %q", err, want)
+	}
+	var se *ScanError
+	if !errors.As(err, &se) || errors.Unwrap(se.Err) == nil {
+		t.Errorf("want the original error to be wrapped, got %v", err)
+	}
+}
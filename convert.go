@@ -7,22 +7,26 @@ package scan
 import (
 	"bytes"
 	"database/sql"
-	"database/sql/driver"
+	"encoding"
 	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 )
 
-var _, localTimeOffset = time.Now().Local().Zone()
+// convertAssign copies to dest the value in src using the default Scanner settings.
+func convertAssign(dest, src any) error {
+	return defaultScanner.convertAssign(dest, src)
+}
 
 // convertAssign copies to dest the value in src, converting it if possible.
 // An error is returned if the copy would result in loss of information.
 // dest should be a pointer type.
 // dest will be set to zero value if src is nil.
 // this function assumes dest will never be nil.
-func convertAssign(dest, src any) error {
+func (sc *Scanner) convertAssign(dest, src any) error {
 	// Common cases, without reflect.
 	switch s := src.(type) {
 	case string:
@@ -55,12 +59,13 @@ func convertAssign(dest, src any) error {
 	case time.Time:
 		switch d := dest.(type) {
 		case *time.Time:
-			// make sure timezone equal for test assertion.
-			if _, offset := s.Zone(); offset == localTimeOffset {
-				*d = s.Local()
-			} else {
-				*d = s
-			}
+			*d = sc.inLocation(s)
+			return nil
+		case *sql.NullTime:
+			d.Time, d.Valid = sc.inLocation(s), true
+			return nil
+		case *sql.Null[time.Time]:
+			d.V, d.Valid = sc.inLocation(s), true
 			return nil
 		case *string:
 			*d = s.Format(time.RFC3339Nano)
@@ -103,9 +108,21 @@ func convertAssign(dest, src any) error {
 			return nil
 		}
 	case *bool:
-		bv, err := driver.Bool.ConvertValue(src)
+		bv, err := sc.convertBool(src)
+		if err == nil {
+			*d = bv
+		}
+		return err
+	case *sql.NullBool:
+		bv, err := sc.convertBool(src)
 		if err == nil {
-			*d = bv.(bool)
+			d.Bool, d.Valid = bv, true
+		}
+		return err
+	case *sql.Null[bool]:
+		bv, err := sc.convertBool(src)
+		if err == nil {
+			d.V, d.Valid = bv, true
 		}
 		return err
 	case *any:
@@ -113,6 +130,22 @@ func convertAssign(dest, src any) error {
 		return nil
 	}
 
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(src)
+	}
+
+	if ok, err := convertStdType(dest, src); ok {
+		return err
+	}
+
+	if ok, err := convertBig(dest, src); ok {
+		return err
+	}
+
+	if ok, err := sc.convertEnum(dest, src); ok {
+		return err
+	}
+
 	dpv := reflect.ValueOf(dest)
 	if dpv.Kind() != reflect.Pointer {
 		return errors.New("destination not a pointer")
@@ -150,14 +183,17 @@ func convertAssign(dest, src any) error {
 			return nil
 		}
 		dv.Set(reflect.New(dv.Type().Elem()))
-		return convertAssign(dv.Interface(), src)
+		return sc.convertAssign(dv.Interface(), src)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		if src == nil {
 			return fmt.Errorf("converting NULL to %s is unsupported", dv.Kind())
 		}
 		s := asString(src)
-		i64, err := strconv.ParseInt(s, 10, dv.Type().Bits())
+		i64, err := sc.parseInt(s, dv.Type().Bits())
 		if err != nil {
+			if ok, uerr := unmarshalAssign(dest, src); ok {
+				return uerr
+			}
 			err = strconvErr(err)
 			return fmt.Errorf("converting driver.Value type %T (%q) to a %s: %v", src, s, dv.Kind(), err)
 		}
@@ -168,8 +204,11 @@ func convertAssign(dest, src any) error {
 			return fmt.Errorf("converting NULL to %s is unsupported", dv.Kind())
 		}
 		s := asString(src)
-		u64, err := strconv.ParseUint(s, 10, dv.Type().Bits())
+		u64, err := sc.parseUint(s, dv.Type().Bits())
 		if err != nil {
+			if ok, uerr := unmarshalAssign(dest, src); ok {
+				return uerr
+			}
 			err = strconvErr(err)
 			return fmt.Errorf("converting driver.Value type %T (%q) to a %s: %v", src, s, dv.Kind(), err)
 		}
@@ -180,13 +219,51 @@ func convertAssign(dest, src any) error {
 			return fmt.Errorf("converting NULL to %s is unsupported", dv.Kind())
 		}
 		s := asString(src)
-		f64, err := strconv.ParseFloat(s, dv.Type().Bits())
+		f64, err := sc.parseFloat(src, s, dv.Type().Bits())
 		if err != nil {
+			if ok, uerr := unmarshalAssign(dest, src); ok {
+				return uerr
+			}
 			err = strconvErr(err)
 			return fmt.Errorf("converting driver.Value type %T (%q) to a %s: %v", src, s, dv.Kind(), err)
 		}
 		dv.SetFloat(f64)
 		return nil
+	case reflect.Array:
+		if dv.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := asFixedBytes(src, dv.Len())
+			if err != nil {
+				return fmt.Errorf("converting driver.Value type %T (%q) to a %s: %v", src, asString(src), dv.Type(), err)
+			}
+			reflect.Copy(dv, reflect.ValueOf(b))
+			return nil
+		}
+		fallthrough
+	case reflect.Slice:
+		// slices decoding themselves, such as JSON arrays, are left to unmarshalAssign
+		if dv.Type().Elem().Kind() == reflect.Uint8 || !isText(src) || isUnmarshaler(dest) {
+			break
+		}
+		if s := strings.TrimSpace(asString(src)); isPgArray(s) {
+			arr, err := parsePgArray(s)
+			if err == nil {
+				err = sc.assignPgArray(dv, arr)
+			}
+			if err != nil {
+				return &ScanError{Value: src, Type: dv.Type(), Err: err}
+			}
+			return nil
+		}
+	case reflect.Bool:
+		if src == nil {
+			return fmt.Errorf("converting NULL to %s is unsupported", dv.Kind())
+		}
+		bv, err := sc.convertBool(src)
+		if err != nil {
+			return err
+		}
+		dv.SetBool(bv)
+		return nil
 	case reflect.String:
 		if src == nil {
 			return fmt.Errorf("converting NULL to %s is unsupported", dv.Kind())
@@ -201,9 +278,65 @@ func convertAssign(dest, src any) error {
 		}
 	}
 
+	// encoding.TextUnmarshaler and encoding.BinaryUnmarshaler are tried last so
+	// that named numeric and string types keep the kind conversions above.
+	// Numeric kinds fall back to them only when the source is not a number.
+	if ok, err := unmarshalAssign(dest, src); ok {
+		return err
+	}
+
 	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", src, dest)
 }
 
+// isUnmarshaler reports whether dest implements encoding.TextUnmarshaler or
+// encoding.BinaryUnmarshaler.
+func isUnmarshaler(dest any) bool {
+	switch dest.(type) {
+	case encoding.TextUnmarshaler, encoding.BinaryUnmarshaler:
+		return true
+	}
+	return false
+}
+
+// unmarshalAssign decodes string and []byte sources with the destination's
+// UnmarshalText, and []byte sources with UnmarshalBinary when UnmarshalText is
+// missing or fails. It reports whether dest implements either method.
+func unmarshalAssign(dest, src any) (bool, error) {
+	var b []byte
+	switch s := src.(type) {
+	case string:
+		b = []byte(s)
+	case []byte:
+		b = s
+	default:
+		return false, nil
+	}
+
+	tu, isText := dest.(encoding.TextUnmarshaler)
+	bu, isBinary := dest.(encoding.BinaryUnmarshaler)
+	if _, ok := src.(string); ok {
+		isBinary = false
+	}
+	if !isText && !isBinary {
+		return false, nil
+	}
+
+	var err error
+	if isText {
+		if err = tu.UnmarshalText(b); err == nil {
+			return true, nil
+		}
+	}
+	if isBinary {
+		if berr := bu.UnmarshalBinary(b); berr == nil {
+			return true, nil
+		} else if err == nil {
+			err = berr
+		}
+	}
+	return true, fmt.Errorf("converting driver.Value type %T (%q) to a %T: %v", src, b, dest, err)
+}
+
 func strconvErr(err error) error {
 	if ne, ok := err.(*strconv.NumError); ok {
 		return ne.Err
@@ -211,6 +344,14 @@ func strconvErr(err error) error {
 	return err
 }
 
+func isText(src any) bool {
+	switch src.(type) {
+	case string, []byte:
+		return true
+	}
+	return false
+}
+
 func asString(src any) string {
 	switch v := src.(type) {
 	case string:
@@ -7,6 +7,7 @@ package scan
 import (
 	"database/sql"
 	"fmt"
+	"math/big"
 	"reflect"
 	"runtime"
 	"testing"
@@ -146,6 +147,7 @@ func conversionTests() []conversionTest {
 		{s: 1, d: &scanbool, wantbool: true},
 		{s: int64(1), d: &scanbool, wantbool: true},
 		{s: uint16(1), d: &scanbool, wantbool: true},
+		{s: []byte{1}, d: &scanbool, wantbool: true},
 
 		// False bools
 		{s: false, d: &scanbool, wantbool: false},
@@ -155,10 +157,13 @@ func conversionTests() []conversionTest {
 		{s: 0, d: &scanbool, wantbool: false},
 		{s: int64(0), d: &scanbool, wantbool: false},
 		{s: uint16(0), d: &scanbool, wantbool: false},
+		{s: []byte{0}, d: &scanbool, wantbool: false},
 
 		// Not bools
 		{s: "yup", d: &scanbool, wanterr: `sql/driver: couldn't convert "yup" into type bool`},
 		{s: 2, d: &scanbool, wanterr: `sql/driver: couldn't convert 2 into type bool`},
+		{s: "Y", d: &scanbool, wanterr: `sql/driver: couldn't convert "Y" into type bool`},
+		{s: []byte{2}, d: &scanbool, wanterr: `sql/driver: couldn't convert "\x02" into type bool`},
 
 		// Floats
 		{s: float64(1.5), d: &scanf64, wantf64: float64(1.5)},
@@ -488,3 +493,169 @@ func TestAssignZero(t *testing.T) {
 		t.Error("vuserDefined is not zero")
 	}
 }
+
+func TestConvertAssignTimeLocation(t *testing.T) {
+	src := time.Date(2024, 1, 2, 3, 4, 5, 6, time.FixedZone("UTC-5", -5*3600))
+	loc := time.FixedZone("UTC+8", 8*3600)
+
+	tests := []struct {
+		name string
+		opts []Option
+		want *time.Location
+	}{
+		{"keep", nil, src.Location()},
+		{"location", []Option{WithTimeLocation(loc)}, loc},
+		{"utc", []Option{WithUTC()}, time.UTC},
+		{"nil location", []Option{WithTimeLocation(nil)}, src.Location()},
+	}
+	for _, tt := range tests {
+		sc := newScanner(tt.opts)
+		check := func(kind string, got time.Time) {
+			if !got.Equal(src) {
+				t.Errorf("%s %s: got %v, want instant %v", tt.name, kind, got, src)
+			}
+			if got.Location() != tt.want {
+				t.Errorf("%s %s: got location %v, want %v", tt.name, kind, got.Location(), tt.want)
+			}
+		}
+
+		var tv time.Time
+		if err := sc.nullable(&tv).(sql.Scanner).Scan(src); err != nil {
+			t.Fatal(err)
+		}
+		check("time.Time", tv)
+
+		var tp *time.Time
+		if err := sc.nullable(&tp).(sql.Scanner).Scan(src); err != nil {
+			t.Fatal(err)
+		}
+		check("*time.Time", *tp)
+
+		var nt sql.Null[time.Time]
+		if err := sc.nullable(&nt).(sql.Scanner).Scan(src); err != nil {
+			t.Fatal(err)
+		}
+		if !nt.Valid {
+			t.Errorf("%s sql.Null[time.Time]: want valid", tt.name)
+		}
+		check("sql.Null[time.Time]", nt.V)
+
+		var ntime sql.NullTime
+		if err := sc.nullable(&ntime).(sql.Scanner).Scan(src); err != nil {
+			t.Fatal(err)
+		}
+		check("sql.NullTime", ntime.Time)
+
+		if err := sc.nullable(&tp).(sql.Scanner).Scan(nil); err != nil || tp != nil {
+			t.Errorf("%s: NULL into *time.Time got %v, %v", tt.name, tp, err)
+		}
+		if err := sc.nullable(&nt).(sql.Scanner).Scan(nil); err != nil || nt.Valid {
+			t.Errorf("%s: NULL into sql.Null[time.Time] got %v, %v", tt.name, nt, err)
+		}
+	}
+}
+
+type textLevel int
+
+func (l *textLevel) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level %q", b)
+	}
+	return nil
+}
+
+type binaryPoint struct{ x, y byte }
+
+func (p *binaryPoint) UnmarshalBinary(b []byte) error {
+	if len(b) != 2 {
+		return fmt.Errorf("want 2 bytes, got %d", len(b))
+	}
+	p.x, p.y = b[0], b[1]
+	return nil
+}
+
+func TestConvertAssignUnmarshalers(t *testing.T) {
+	var level textLevel
+	if err := convertAssign(&level, []byte("high")); err != nil || level != 2 {
+		t.Errorf("UnmarshalText from []byte: got %v, %v", level, err)
+	}
+	if err := convertAssign(&level, "low"); err != nil || level != 1 {
+		t.Errorf("UnmarshalText from string: got %v, %v", level, err)
+	}
+	err := convertAssign(&level, "medium")
+	if want := `converting driver.Value type string ("medium") to a *scan.textLevel: unknown level "medium"`; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+
+	var p binaryPoint
+	if err := convertAssign(&p, []byte{3, 4}); err != nil || p != (binaryPoint{3, 4}) {
+		t.Errorf("UnmarshalBinary: got %v, %v", p, err)
+	}
+	err = convertAssign(&p, "ab")
+	if want := `unsupported Scan, storing driver.Value type string into type *scan.binaryPoint`; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+
+	var n big.Int
+	if err := convertAssign(&n, []byte("123456789012345678901234567890")); err != nil || n.String() != "123456789012345678901234567890" {
+		t.Errorf("big.Int: got %v, %v", n.String(), err)
+	}
+}
+
+type textOrBinary struct{ s string }
+
+func (v *textOrBinary) UnmarshalText(b []byte) error {
+	if len(b) == 0 || b[0] < ' ' {
+		return fmt.Errorf("not text")
+	}
+	v.s = "text:" + string(b)
+	return nil
+}
+
+func (v *textOrBinary) UnmarshalBinary(b []byte) error {
+	v.s = fmt.Sprintf("binary:%x", b)
+	return nil
+}
+
+func TestConvertAssignBinaryFallback(t *testing.T) {
+	var v textOrBinary
+	if err := convertAssign(&v, 42); err == nil {
+		t.Errorf("want error for non-text source, got %q", v.s)
+	}
+	if err := convertAssign(&v, []byte("abc")); err != nil || v.s != "text:abc" {
+		t.Errorf("got %q, %v", v.s, err)
+	}
+	if err := convertAssign(&v, []byte{1, 2}); err != nil || v.s != "binary:0102" {
+		t.Errorf("got %q, %v", v.s, err)
+	}
+}
+
+type prefixedText string
+
+func (v *prefixedText) UnmarshalText(b []byte) error {
+	*v = prefixedText("text:" + string(b))
+	return nil
+}
+
+func TestConvertAssignUnmarshalerAfterKinds(t *testing.T) {
+	var level textLevel
+	if err := convertAssign(&level, []byte("1")); err != nil || level != 1 {
+		t.Errorf("numeric []byte into textLevel: got %v, %v", level, err)
+	}
+	if err := convertAssign(&level, int64(2)); err != nil || level != 2 {
+		t.Errorf("int64 into textLevel: got %v, %v", level, err)
+	}
+	if err := convertAssign(&level, "low"); err != nil || level != 1 {
+		t.Errorf("label into textLevel: got %v, %v", level, err)
+	}
+
+	var u prefixedText
+	if err := convertAssign(&u, []byte("abc")); err != nil || u != "abc" {
+		t.Errorf("[]byte into prefixedText: got %q, %v", u, err)
+	}
+}
@@ -0,0 +1,134 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var errLosesPrecision = errors.New("value loses precision")
+
+// convertBig assigns integer, float and decimal string sources to *big.Int,
+// *big.Float and *big.Rat destinations without going through float64.
+// It reports whether dest was handled.
+func convertBig(dest, src any) (bool, error) {
+	switch dest.(type) {
+	case *big.Int, *big.Float, *big.Rat:
+	default:
+		return false, nil
+	}
+
+	var err error
+	switch d := dest.(type) {
+	case *big.Int:
+		err = setBigInt(d, src)
+	case *big.Float:
+		err = setBigFloat(d, src)
+	case *big.Rat:
+		err = setBigRat(d, src)
+	}
+	if err != nil {
+		return true, fmt.Errorf("converting driver.Value type %T (%q) to a %T: %v", src, asString(src), dest, err)
+	}
+	return true, nil
+}
+
+func setBigInt(d *big.Int, src any) error {
+	rv := reflect.ValueOf(src)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		d.SetInt64(rv.Int())
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		d.SetUint64(rv.Uint())
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsInf(f, 0) || math.IsNaN(f) || f != math.Trunc(f) {
+			return errLosesPrecision
+		}
+		big.NewFloat(f).Int(d)
+		return nil
+	case reflect.String, reflect.Slice:
+		// DECIMAL(n,0) and DECIMAL values with a zero fraction are accepted.
+		r, ok := new(big.Rat).SetString(strings.TrimSpace(asString(src)))
+		if !ok {
+			return strconv.ErrSyntax
+		}
+		if !r.IsInt() {
+			return errLosesPrecision
+		}
+		d.Set(r.Num())
+		return nil
+	}
+	return errors.New("unsupported type")
+}
+
+func setBigFloat(d *big.Float, src any) error {
+	rv := reflect.ValueOf(src)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		d.SetInt64(rv.Int())
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		d.SetUint64(rv.Uint())
+		return nil
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(rv.Float()) {
+			return strconv.ErrSyntax
+		}
+		d.SetFloat64(rv.Float())
+		return nil
+	case reflect.String, reflect.Slice:
+		s := strings.TrimSpace(asString(src))
+		if d.Prec() == 0 {
+			// about 3.3 bits per decimal digit, never less than float64
+			d.SetPrec(max(64, uint(len(s))*4))
+		}
+		if _, ok := d.SetString(s); !ok {
+			return strconv.ErrSyntax
+		}
+		return nil
+	}
+	return errors.New("unsupported type")
+}
+
+func setBigRat(d *big.Rat, src any) error {
+	rv := reflect.ValueOf(src)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		d.SetInt64(rv.Int())
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		d.SetUint64(rv.Uint())
+		return nil
+	case reflect.Float32, reflect.Float64:
+		if math.IsInf(rv.Float(), 0) || math.IsNaN(rv.Float()) {
+			return strconv.ErrSyntax
+		}
+		d.SetFloat64(rv.Float())
+		return nil
+	case reflect.String, reflect.Slice:
+		if _, ok := d.SetString(strings.TrimSpace(asString(src))); !ok {
+			return strconv.ErrSyntax
+		}
+		return nil
+	}
+	return errors.New("unsupported type")
+}
+
+// decimalFits reports whether f, parsed from the decimal string s, formats
+// back to the same decimal value, i.e. whether no digits were lost.
+func decimalFits(s string, f float64, bitSize int) bool {
+	want, ok := new(big.Rat).SetString(s)
+	if !ok {
+		// Inf and NaN have no decimal representation to lose.
+		return true
+	}
+	got, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, bitSize))
+	return ok && want.Cmp(got) == 0
+}
@@ -0,0 +1,94 @@
+package scan
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestConvertBig(t *testing.T) {
+	const decimal = "12345678901234567890.1234567890"
+
+	tests := []struct {
+		s, d    any
+		want    string
+		wanterr string
+	}{
+		{s: int64(-42), d: new(big.Int), want: "-42"},
+		{s: uint64(1 << 63), d: new(big.Int), want: "9223372036854775808"},
+		{s: float64(1e20), d: new(big.Int), want: "100000000000000000000"},
+		{s: []byte("123456789012345678901234567890"), d: new(big.Int), want: "123456789012345678901234567890"},
+		{s: []byte("42.000"), d: new(big.Int), want: "42"},
+		{s: 1.5, d: new(big.Int), wanterr: `converting driver.Value type float64 ("1.5") to a *big.Int: value loses precision`},
+		{s: "42.5", d: new(big.Int), wanterr: `converting driver.Value type string ("42.5") to a *big.Int: value loses precision`},
+		{s: "abc", d: new(big.Int), wanterr: `converting driver.Value type string ("abc") to a *big.Int: invalid syntax`},
+		{s: true, d: new(big.Int), wanterr: `converting driver.Value type bool ("true") to a *big.Int: unsupported type`},
+
+		{s: []byte(decimal), d: new(big.Rat), want: "12345678901234567890123456789/1000000000"},
+		{s: int64(3), d: new(big.Rat), want: "3/1"},
+		{s: 0.5, d: new(big.Rat), want: "1/2"},
+
+		{s: []byte(decimal), d: new(big.Float), want: decimal},
+		{s: int64(7), d: new(big.Float), want: "7"},
+	}
+	for _, tt := range tests {
+		err := convertAssign(tt.d, tt.s)
+		errstr := ""
+		if err != nil {
+			errstr = err.Error()
+		}
+		if errstr != tt.wanterr {
+			t.Errorf("convertAssign(%T, %v): got error %q, want %q", tt.d, tt.s, errstr, tt.wanterr)
+			continue
+		}
+		if tt.wanterr != "" {
+			continue
+		}
+		var got string
+		switch d := tt.d.(type) {
+		case *big.Int:
+			got = d.String()
+		case *big.Rat:
+			got = d.String()
+		case *big.Float:
+			got = d.Text('f', 10)
+			if d.IsInt() {
+				got = d.Text('f', 0)
+			}
+		}
+		if got != tt.want {
+			t.Errorf("convertAssign(%T, %v): got %s, want %s", tt.d, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestLosslessFloats(t *testing.T) {
+	exact := newScanner([]Option{WithNumericPolicy(NumericLossless)})
+
+	tests := []struct {
+		s       any
+		d       any
+		wanterr string
+	}{
+		{s: []byte("0.1"), d: new(float64)},
+		{s: []byte("1.500"), d: new(float64)},
+		{s: []byte("1e300"), d: new(float64)},
+		{s: float64(0.1), d: new(float32), wanterr: `converting driver.Value type float64 ("0.1") to a float32: value loses precision`},
+		{s: []byte("9007199254740993"), d: new(float64), wanterr: `converting driver.Value type []uint8 ("9007199254740993") to a float64: value loses precision`},
+		{s: []byte("12345678901234567890.1234567890"), d: new(float64), wanterr: `converting driver.Value type []uint8 ("12345678901234567890.1234567890") to a float64: value loses precision`},
+		{s: "16777217", d: new(float32), wanterr: `converting driver.Value type string ("16777217") to a float32: value loses precision`},
+		{s: "0.1", d: new(float32)},
+	}
+	for _, tt := range tests {
+		err := exact.convertAssign(tt.d, tt.s)
+		errstr := ""
+		if err != nil {
+			errstr = err.Error()
+		}
+		if errstr != tt.wanterr {
+			t.Errorf("convertAssign(%T, %v): got error %q, want %q", tt.d, tt.s, errstr, tt.wanterr)
+		}
+		if err := convertAssign(tt.d, tt.s); err != nil {
+			t.Errorf("default scanner convertAssign(%T, %v): %v", tt.d, tt.s, err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package scan
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	enumsMu sync.RWMutex
+	enums   = make(map[reflect.Type]*enumType)
+	// hasEnums is set by the first RegisterEnum, so that text columns skip
+	// the enum lookup while none are registered.
+	hasEnums atomic.Bool
+)
+
+// enumType holds the labels registered for an enum type.
+type enumType struct {
+	values map[string]reflect.Value
+	labels map[any]string
+	// names lists the valid labels in sorted order for error messages.
+	names []string
+}
+
+// RegisterEnum maps the string labels stored in the database to the values of
+// the enum type T, so that string and []byte columns can be scanned into T.
+// RegisterEnum replaces any mapping previously registered for T.
+// If several labels map to the same value, EnumLabel returns the first in sorted order.
+func RegisterEnum[T comparable](labels map[string]T) {
+	e := &enumType{
+		values: make(map[string]reflect.Value, len(labels)),
+		labels: make(map[any]string, len(labels)),
+	}
+	for label := range labels {
+		e.names = append(e.names, label)
+	}
+	slices.Sort(e.names)
+	for _, label := range e.names {
+		v := labels[label]
+		e.values[label] = reflect.ValueOf(v)
+		if _, ok := e.labels[v]; !ok {
+			e.labels[v] = label
+		}
+	}
+
+	enumsMu.Lock()
+	defer enumsMu.Unlock()
+	enums[reflect.TypeFor[T]()] = e
+	hasEnums.Store(true)
+}
+
+// EnumLabel returns the label registered for v with RegisterEnum.
+func EnumLabel[T comparable](v T) (string, bool) {
+	e := lookupEnum(reflect.TypeFor[T]())
+	if e == nil {
+		return "", false
+	}
+	label, ok := e.labels[v]
+	return label, ok
+}
+
+func lookupEnum(t reflect.Type) *enumType {
+	enumsMu.RLock()
+	defer enumsMu.RUnlock()
+	return enums[t]
+}
+
+// convertEnum assigns string and []byte sources to destinations whose type
+// was registered with RegisterEnum. Sources that are not a registered label
+// are parsed as numbers when the enum has an integer kind, so "2" is accepted
+// like the integer 2. It reports whether dest was handled.
+func (sc *Scanner) convertEnum(dest, src any) (bool, error) {
+	if !hasEnums.Load() || !isText(src) {
+		return false, nil
+	}
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer {
+		return false, nil
+	}
+	e := lookupEnum(dv.Type().Elem())
+	if e == nil {
+		return false, nil
+	}
+
+	label := asString(src)
+	if v, ok := e.values[label]; ok {
+		dv.Elem().Set(v)
+		return true, nil
+	}
+	if sc.setEnumNumber(dv.Elem(), label) {
+		return true, nil
+	}
+
+	quoted := make([]string, len(e.names))
+	for i, name := range e.names {
+		quoted[i] = strconv.Quote(name)
+	}
+	return true, &ScanError{
+		Value: src,
+		Type:  dv.Type().Elem(),
+		Err:   fmt.Errorf("unknown label, valid values are %s", strings.Join(quoted, ", ")),
+	}
+}
+
+// setEnumNumber parses s into an enum value with an integer kind and reports whether it succeeded.
+func (sc *Scanner) setEnumNumber(v reflect.Value, s string) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i64, err := sc.parseInt(s, v.Type().Bits())
+		if err != nil {
+			return false
+		}
+		v.SetInt(i64)
+		return true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u64, err := sc.parseUint(s, v.Type().Bits())
+		if err != nil {
+			return false
+		}
+		v.SetUint(u64)
+		return true
+	}
+	return false
+}
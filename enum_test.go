@@ -0,0 +1,84 @@
+package scan
+
+import (
+	"errors"
+	"testing"
+)
+
+type testStatus int
+
+const (
+	testStatusActive testStatus = iota + 1
+	testStatusSuspended
+)
+
+func init() {
+	RegisterEnum(map[string]testStatus{
+		"active":    testStatusActive,
+		"enabled":   testStatusActive,
+		"suspended": testStatusSuspended,
+	})
+}
+
+func TestConvertEnum(t *testing.T) {
+	var s testStatus
+	if err := convertAssign(&s, "suspended"); err != nil || s != testStatusSuspended {
+		t.Errorf("string label: got %v, %v", s, err)
+	}
+	if err := convertAssign(&s, []byte("enabled")); err != nil || s != testStatusActive {
+		t.Errorf("[]byte label: got %v, %v", s, err)
+	}
+	if err := convertAssign(&s, int64(2)); err != nil || s != testStatusSuspended {
+		t.Errorf("integer source: got %v, %v", s, err)
+	}
+	if err := convertAssign(&s, []byte("1")); err != nil || s != testStatusActive {
+		t.Errorf("numeric []byte source: got %v, %v", s, err)
+	}
+	if err := convertAssign(&s, "2"); err != nil || s != testStatusSuspended {
+		t.Errorf("numeric string source: got %v, %v", s, err)
+	}
+
+	var sp *testStatus
+	if err := convertAssign(&sp, "active"); err != nil || sp == nil || *sp != testStatusActive {
+		t.Errorf("pointer destination: got %v, %v", sp, err)
+	}
+
+	err := convertAssign(&s, "deleted")
+	var se *ScanError
+	if !errors.As(err, &se) {
+		t.Fatalf("want *ScanError, got %v", err)
+	}
+	if se.Value != "deleted" || se.Type.Name() != "testStatus" {
+		t.Errorf("unexpected ScanError %+v", se)
+	}
+	want := `converting driver.Value type string ("deleted") to a scan.testStatus: unknown label, valid values are "active", "enabled", "suspended"`
+	if err.Error() != want {
+		t.Errorf("got error %q, want %q", err, want)
+	}
+}
+
+func TestEnumLabel(t *testing.T) {
+	if label, ok := EnumLabel(testStatusSuspended); !ok || label != "suspended" {
+		t.Errorf("got %q, %v", label, ok)
+	}
+	// the first label in sorted order wins for values with several labels
+	if label, ok := EnumLabel(testStatusActive); !ok || label != "active" {
+		t.Errorf("got %q, %v", label, ok)
+	}
+	if _, ok := EnumLabel(testStatus(42)); ok {
+		t.Error("want no label for an unregistered value")
+	}
+	if _, ok := EnumLabel(42); ok {
+		t.Error("want no label for an unregistered type")
+	}
+}
+
+func TestScanErrorColumn(t *testing.T) {
+	var s testStatus
+	c := columnScanner{dest: defaultScanner.nullable(&s), column: "status"}
+	err := c.Scan("deleted")
+	want := `column "status": converting driver.Value type string ("deleted") to a scan.testStatus: unknown label, valid values are "active", "enabled", "suspended"`
+	if err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+}
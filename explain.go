@@ -0,0 +1,289 @@
+package scan
+
+import (
+	"database/sql"
+	"encoding"
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// MatchKind tells how a column was matched to a struct field.
+type MatchKind int
+
+const (
+	// MatchNone means the column has no field and is discarded.
+	MatchNone MatchKind = iota
+	// MatchTag means the column matched the name in a `db` tag.
+	MatchTag
+	// MatchMapper means the column matched a field by name, through ScannerMapper
+	// or the mapper set by WithMapper.
+	MatchMapper
+	// MatchExtra means the column is collected by the field tagged `db:",extra"`.
+	MatchExtra
+	// MatchAlias means a table qualified column, such as users.id, matched a
+	// field through its table alias, as enabled by WithTableQualifiers.
+	MatchAlias
+)
+
+func (k MatchKind) String() string {
+	switch k {
+	case MatchTag:
+		return "tag"
+	case MatchMapper:
+		return "mapper"
+	case MatchExtra:
+		return "extra"
+	case MatchAlias:
+		return "alias"
+	}
+	return "none"
+}
+
+// ColumnMatch describes where a column is scanned into.
+type ColumnMatch struct {
+	Column string
+	// Field is the path of the field, e.g. "Base.ID", empty when there is none.
+	Field string
+	Match MatchKind
+	// Converter names how the value is converted into the field.
+	Converter string
+}
+
+// Explanation describes how Row and Rows map a set of columns to a struct type.
+type Explanation struct {
+	Type    reflect.Type
+	Columns []ColumnMatch
+	// Unmapped lists the columns that are discarded.
+	Unmapped []string
+	// Unfilled lists the paths of the settable fields that no column fills.
+	Unfilled []string
+}
+
+// Explain reports how Row and Rows would scan columns into T, using the
+// same rules and options. It is meant for debugging mappings in logs and tests.
+func Explain[T any](columns []string, opts ...Option) (*Explanation, error) {
+	typ := rowType(reflect.TypeFor[T]())
+	if reflect.PointerTo(typ).Implements(rowScannerType) || typ.Kind() != reflect.Struct || implementsSetter(typ) {
+		return nil, fmt.Errorf("scan: cannot explain %s, only structs are mapped by field", typ)
+	}
+	sc := newScanner(opts)
+	mapping, err := newStructMapping(sc, typ, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Explanation{Type: typ, Columns: make([]ColumnMatch, len(columns))}
+	item := reflect.New(typ).Elem()
+	filled := make(map[string]bool)
+	for i, f := range mapping.fields {
+		cm := ColumnMatch{Column: columns[i]}
+		var fv reflect.Value
+		if f.index != nil {
+			fv = fieldByIndex(item, f.index)
+		}
+		switch {
+		case fv.IsValid() && fv.CanSet():
+			cm.Field = fieldPath(typ, f.index)
+			cm.Match = f.match
+			cm.Converter = converterOf(fv.Type(), f.tag)
+			filled[cm.Field] = true
+		case mapping.extra != nil:
+			cm.Field = fieldPath(typ, mapping.extra)
+			cm.Match = MatchExtra
+			cm.Converter = converterOf(typ.FieldByIndex(mapping.extra).Type.Elem(), fieldTag{})
+		default:
+			e.Unmapped = append(e.Unmapped, cm.Column)
+		}
+		e.Columns[i] = cm
+	}
+
+	var extra string
+	if mapping.extra != nil {
+		extra = fieldPath(typ, mapping.extra)
+	}
+	for _, path := range sc.settableFields(typ, nil, "") {
+		if path != extra && !filled[path] && !hasFilledPrefix(filled, path) {
+			e.Unfilled = append(e.Unfilled, path)
+		}
+	}
+	return e, nil
+}
+
+// String formats the explanation as a table.
+func (e *Explanation) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", e.Type)
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "  COLUMN\tFIELD\tMATCH\tCONVERTER")
+	for _, c := range e.Columns {
+		field := c.Field
+		if field == "" {
+			field = "-"
+		}
+		conv := c.Converter
+		if conv == "" {
+			conv = "-"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", c.Column, field, c.Match, conv)
+	}
+	w.Flush()
+	if len(e.Unmapped) > 0 {
+		fmt.Fprintf(&b, "unmapped columns: %s\n", strings.Join(e.Unmapped, ", "))
+	}
+	if len(e.Unfilled) > 0 {
+		fmt.Fprintf(&b, "unfilled fields: %s\n", strings.Join(e.Unfilled, ", "))
+	}
+	return b.String()
+}
+
+// fieldPath returns the dotted names of the fields along index.
+func fieldPath(typ reflect.Type, index []int) string {
+	names := make([]string, len(index))
+	for i, x := range index {
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		sf := typ.Field(x)
+		names[i] = sf.Name
+		typ = sf.Type
+	}
+	return strings.Join(names, ".")
+}
+
+// settableFields returns the paths of the fields of typ a column can fill.
+// Embedded structs and structs holding tagged fields are replaced by their
+// own fields.
+func (sc *Scanner) settableFields(typ reflect.Type, parents []reflect.Type, prefix string) []string {
+	parents = append(parents, typ)
+	var paths []string
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if sc.structTag(sf).ignored {
+			continue
+		}
+		if ft, ok := nestedStruct(sf, parents); ok && (sf.Anonymous || sc.hasTaggedField(ft, parents)) {
+			if sf.IsExported() || sf.Anonymous {
+				paths = append(paths, sc.settableFields(ft, parents, prefix+sf.Name+".")...)
+			}
+			continue
+		}
+		if sf.IsExported() {
+			paths = append(paths, prefix+sf.Name)
+		}
+	}
+	return paths
+}
+
+func (sc *Scanner) hasTaggedField(typ reflect.Type, parents []reflect.Type) bool {
+	parents = append(parents, typ)
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		ft, ok := sc.lookupTag(sf)
+		if ft.ignored {
+			continue
+		}
+		if ok {
+			return true
+		}
+		if nested, ok := nestedStruct(sf, parents); ok && sc.hasTaggedField(nested, parents) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFilledPrefix reports whether a field containing path is filled.
+func hasFilledPrefix(filled map[string]bool, path string) bool {
+	for i := strings.LastIndexByte(path, '.'); i > 0; i = strings.LastIndexByte(path[:i], '.') {
+		if filled[path[:i]] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	textUnmarshalerType   = reflect.TypeFor[encoding.TextUnmarshaler]()
+	binaryUnmarshalerType = reflect.TypeFor[encoding.BinaryUnmarshaler]()
+	sqlScannerType        = reflect.TypeFor[sql.Scanner]()
+)
+
+// converterOf names the conversion used for a field of type t with tag.
+func converterOf(t reflect.Type, tag fieldTag) string {
+	var conv string
+	switch {
+	case tag.unit != 0:
+		for name, unit := range unixUnits {
+			if unit == tag.unit {
+				conv = name
+			}
+		}
+	case tag.split != "":
+		conv = fmt.Sprintf("split %q", tag.split)
+	default:
+		for t.Kind() == reflect.Pointer && !reflect.PointerTo(t).Implements(sqlScannerType) {
+			t = t.Elem()
+		}
+		conv = destConverter(t)
+	}
+	if tag.codec != "" {
+		conv = "codec " + tag.codec + ", " + conv
+	}
+	return conv
+}
+
+// destConverter names the conversion convertAssign uses for a destination of type t.
+func destConverter(t reflect.Type) string {
+	pt := reflect.PointerTo(t)
+	switch {
+	case pt.Implements(sqlScannerType):
+		return "sql.Scanner"
+	case t == reflect.TypeFor[time.Duration]():
+		return "duration"
+	case t == reflect.TypeFor[netip.Addr]() || t == reflect.TypeFor[net.IP]():
+		return "ip address"
+	case t == reflect.TypeFor[netip.Prefix]():
+		return "ip prefix"
+	case t == reflect.TypeFor[time.Time]():
+		return "time"
+	case t == reflect.TypeFor[big.Int]() || t == reflect.TypeFor[big.Float]() || t == reflect.TypeFor[big.Rat]():
+		return "math/big"
+	case lookupEnum(t) != nil:
+		return "enum"
+	case t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8:
+		if t.Len() == 16 {
+			return "uuid"
+		}
+		return fmt.Sprintf("[%d]byte", t.Len())
+	case isBasicKind(t.Kind()):
+		// numbers, strings and bools convert by kind before any unmarshaler
+		return t.String()
+	case pt.Implements(textUnmarshalerType):
+		return "encoding.TextUnmarshaler"
+	case pt.Implements(binaryUnmarshalerType):
+		return "encoding.BinaryUnmarshaler"
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8,
+		t.Kind() == reflect.Array && t.Elem().Kind() != reflect.Uint8:
+		return "array literal"
+	}
+	return t.String()
+}
+
+// isBasicKind reports whether convertAssign converts values of kind k by
+// their kind.
+func isBasicKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package scan
+
+import (
+	"database/sql"
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExplain(t *testing.T) {
+	type Base struct {
+		ID        int64     `db:"id"`
+		CreatedAt time.Time `db:"created_at,unixmilli"`
+	}
+	type User struct {
+		Base
+		Name     string
+		Email    sql.NullString `db:"email_address"`
+		Tags     []string       `db:"tags,split"`
+		Nickname *string
+		Score    float64
+		secret   string `db:"secret"`
+	}
+
+	e, err := Explain[User]([]string{"id", "created_at", "name", "email_address", "tags", "nickname", "secret", "unknown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ColumnMatch{
+		{Column: "id", Field: "Base.ID", Match: MatchTag, Converter: "int64"},
+		{Column: "created_at", Field: "Base.CreatedAt", Match: MatchTag, Converter: "unixmilli"},
+		{Column: "name", Field: "Name", Match: MatchMapper, Converter: "string"},
+		{Column: "email_address", Field: "Email", Match: MatchTag, Converter: "sql.Scanner"},
+		{Column: "tags", Field: "Tags", Match: MatchTag, Converter: `split ","`},
+		{Column: "nickname", Field: "Nickname", Match: MatchMapper, Converter: "string"},
+		{Column: "secret"},
+		{Column: "unknown"},
+	}
+	if !reflect.DeepEqual(e.Columns, want) {
+		t.Errorf("Columns = %+v, want %+v", e.Columns, want)
+	}
+	if want := []string{"secret", "unknown"}; !reflect.DeepEqual(e.Unmapped, want) {
+		t.Errorf("Unmapped = %q, want %q", e.Unmapped, want)
+	}
+	if want := []string{"Score"}; !reflect.DeepEqual(e.Unfilled, want) {
+		t.Errorf("Unfilled = %q, want %q", e.Unfilled, want)
+	}
+
+	wantString := `scan.User
+  COLUMN         FIELD           MATCH   CONVERTER
+  id             Base.ID         tag     int64
+  created_at     Base.CreatedAt  tag     unixmilli
+  name           Name            mapper  string
+  email_address  Email           tag     sql.Scanner
+  tags           Tags            tag     split ","
+  nickname       Nickname        mapper  string
+  secret         -               none    -
+  unknown        -               none    -
+unmapped columns: secret, unknown
+unfilled fields: Score
+`
+	if got := e.String(); got != wantString {
+		t.Errorf("String() =\n%s\nwant\n%s", got, wantString)
+	}
+}
+
+func TestExplainEmbeddedPointer(t *testing.T) {
+	type Base struct {
+		ID int64 `db:"id"`
+	}
+	type Order struct {
+		*Base
+		Total float64
+	}
+	e, err := Explain[Order]([]string{"id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ColumnMatch{{Column: "id", Field: "Base.ID", Match: MatchTag, Converter: "int64"}}
+	if !reflect.DeepEqual(e.Columns, want) {
+		t.Errorf("Columns = %+v, want %+v", e.Columns, want)
+	}
+	if want := []string{"Total"}; !reflect.DeepEqual(e.Unfilled, want) {
+		t.Errorf("Unfilled = %q, want %q", e.Unfilled, want)
+	}
+}
+
+func TestExplainExtra(t *testing.T) {
+	type Report struct {
+		Title  string
+		Extras map[string]string `db:",extra"`
+	}
+	e, err := Explain[Report]([]string{"total"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ColumnMatch{{Column: "total", Field: "Extras", Match: MatchExtra, Converter: "string"}}
+	if !reflect.DeepEqual(e.Columns, want) {
+		t.Errorf("Columns = %+v, want %+v", e.Columns, want)
+	}
+	if e.Unmapped != nil {
+		t.Errorf("Unmapped = %q, want none", e.Unmapped)
+	}
+	if want := []string{"Title"}; !reflect.DeepEqual(e.Unfilled, want) {
+		t.Errorf("Unfilled = %q, want %q", e.Unfilled, want)
+	}
+}
+
+func TestExplainStdTypesAndAliases(t *testing.T) {
+	type User struct {
+		ID int64 `db:"id"`
+	}
+	type Session struct {
+		User    User
+		Token   [16]byte
+		Key     [4]byte
+		Addr    netip.Addr
+		IP      net.IP `db:"ip"`
+		Network netip.Prefix
+		TTL     time.Duration `db:"ttl"`
+	}
+	e, err := Explain[Session]([]string{"users.id", "token", "key", "addr", "ip", "network", "ttl"}, WithTableQualifiers())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ColumnMatch{
+		{Column: "users.id", Field: "User.ID", Match: MatchAlias, Converter: "int64"},
+		{Column: "token", Field: "Token", Match: MatchMapper, Converter: "uuid"},
+		{Column: "key", Field: "Key", Match: MatchMapper, Converter: "[4]byte"},
+		{Column: "addr", Field: "Addr", Match: MatchMapper, Converter: "ip address"},
+		{Column: "ip", Field: "IP", Match: MatchTag, Converter: "ip address"},
+		{Column: "network", Field: "Network", Match: MatchMapper, Converter: "ip prefix"},
+		{Column: "ttl", Field: "TTL", Match: MatchTag, Converter: "duration"},
+	}
+	if !reflect.DeepEqual(e.Columns, want) {
+		t.Errorf("Columns = %+v, want %+v", e.Columns, want)
+	}
+}
+
+func TestExplainUnmarshalerKinds(t *testing.T) {
+	type Ticket struct {
+		Level  textLevel
+		Label  prefixedText
+		Status jsonTags
+		Point  binaryPoint
+	}
+	e, err := Explain[Ticket]([]string{"level", "label", "status", "point"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ColumnMatch{
+		{Column: "level", Field: "Level", Match: MatchMapper, Converter: "scan.textLevel"},
+		{Column: "label", Field: "Label", Match: MatchMapper, Converter: "scan.prefixedText"},
+		{Column: "status", Field: "Status", Match: MatchMapper, Converter: "encoding.TextUnmarshaler"},
+		{Column: "point", Field: "Point", Match: MatchMapper, Converter: "encoding.BinaryUnmarshaler"},
+	}
+	if !reflect.DeepEqual(e.Columns, want) {
+		t.Errorf("Columns = %+v, want %+v", e.Columns, want)
+	}
+}
+
+func TestExplainRejectsNonStructs(t *testing.T) {
+	if _, err := Explain[int]([]string{"id"}); err == nil {
+		t.Fatal("expected error for int")
+	}
+}
@@ -0,0 +1,151 @@
+package scan
+
+import (
+	"database/sql"
+	"reflect"
+	"sync"
+)
+
+// prepareFunc returns the r.Scan destinations of cols and a function binding
+// them to the item a row is scanned into.
+type prepareFunc func(sc *Scanner, cols []string) ([]any, func(item any))
+
+var (
+	generatedMu sync.RWMutex
+	generated   = make(map[reflect.Type]prepareFunc)
+)
+
+// RegisterScanner registers the scanner generated by cmd/scangen for the
+// struct type T, which Row, Rows and the other functions of this package then
+// use instead of reflection. For the columns of a query, prepare returns one
+// r.Scan destination per column and a function binding them to the fields of
+// the value each row is scanned into.
+//
+// Generated scanners implement the default mapping rules, so they are not
+// used when WithMapper, WithIgnoreCase, WithTableQualifiers,
+// WithStrictColumns or WithTagSources change them.
+func RegisterScanner[T any](prepare func(sc *Scanner, cols []string) ([]any, func(*T))) {
+	typ := reflect.TypeFor[T]()
+	p := func(sc *Scanner, cols []string) ([]any, func(item any)) {
+		dests, bind := prepare(sc, cols)
+		return dests, func(item any) {
+			bind(item.(*T))
+		}
+	}
+
+	generatedMu.Lock()
+	defer generatedMu.Unlock()
+	generated[typ] = p
+}
+
+func lookupGenerated(t reflect.Type) prepareFunc {
+	generatedMu.RLock()
+	defer generatedMu.RUnlock()
+	return generated[t]
+}
+
+// defaultMapping reports whether sc maps columns to fields by the default
+// rules, which generated scanners implement.
+func (sc *Scanner) defaultMapping() bool {
+	return sc.mapper == nil && !sc.ignoreCase && !sc.qualifiers && !sc.strictColumns && sc.tagSources == nil
+}
+
+// scanGenerated scans rows into the values next returns with the generated
+// scanner prepare. The destinations are prepared once and bound to each row.
+func scanGenerated(r *sql.Rows, sc *Scanner, cols []string, prepare prepareFunc, key *keyColumn, next func() any) error {
+	dests, bind := prepare(sc, cols)
+	for r.Next() {
+		item := next()
+		if item == nil {
+			break
+		}
+		bind(item)
+		if err := key.scan(r, sc, dests); err != nil {
+			return err
+		}
+	}
+	return r.Err()
+}
+
+// Field is the r.Scan destination of a column scanned into a struct field of
+// type T by a generated scanner. It is created once per query and bound to
+// the field of each row in turn, and converts values as Row and Rows do.
+type Field[T any] struct {
+	ptr    *T
+	sc     *Scanner
+	column string
+	tag    fieldTag
+}
+
+// NewField returns the destination of column for a field of type T whose db
+// tag is tag.
+func NewField[T any](sc *Scanner, column, tag string) *Field[T] {
+	return &Field[T]{sc: sc, column: column, tag: parseTag(tag)}
+}
+
+// Bind makes f scan into the field p.
+func (f *Field[T]) Bind(p *T) {
+	f.ptr = p
+}
+
+func (f *Field[T]) Scan(src any) error {
+	return withColumn(f.scan(src), f.column)
+}
+
+func (f *Field[T]) scan(src any) error {
+	if !f.tag.plain() {
+		return f.sc.fieldDest(reflect.ValueOf(f.ptr).Elem(), f.tag).Scan(src)
+	}
+	// Common cases, without reflect.
+	switch p := any(f.ptr).(type) {
+	case *int64:
+		if s, ok := src.(int64); ok {
+			*p = s
+			return nil
+		}
+	case *int:
+		if s, ok := src.(int64); ok && int64(int(s)) == s {
+			*p = int(s)
+			return nil
+		}
+	case *float64:
+		if s, ok := src.(float64); ok {
+			*p = s
+			return nil
+		}
+	}
+	if s, ok := selfScanner(f.ptr); ok {
+		return s.Scan(src)
+	}
+	return f.sc.convertAssign(f.ptr, src)
+}
+
+// Extra is the r.Scan destination of a column stored in the extra map field
+// of type M by a generated scanner. Like Field, it is bound to each row in turn.
+type Extra[M ~map[K]E, K ~string, E any] struct {
+	extras *M
+	sc     *Scanner
+	column string
+}
+
+// NewExtra returns the destination of column for an extra map field of type M.
+func NewExtra[M ~map[K]E, K ~string, E any](sc *Scanner, column string) *Extra[M, K, E] {
+	return &Extra[M, K, E]{sc: sc, column: column}
+}
+
+// Bind makes e store into the map field p, which it allocates when nil.
+func (e *Extra[M, K, E]) Bind(p *M) {
+	if *p == nil {
+		*p = make(M)
+	}
+	e.extras = p
+}
+
+func (e *Extra[M, K, E]) Scan(src any) error {
+	var v E
+	if err := e.sc.convertAssign(&v, src); err != nil {
+		return withColumn(err, e.column)
+	}
+	(*e.extras)[K(e.column)] = v
+	return nil
+}
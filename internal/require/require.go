@@ -28,3 +28,16 @@ func Len(t testing.TB, v any, n int, _ ...any) {
 		t.Fatalf("len not supported for kind: %s", rv.Kind().String())
 	}
 }
+
+func NotNil(t testing.TB, v any, _ ...any) {
+	if v == nil {
+		t.Fatalf("unexpected nil")
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Interface, reflect.Chan:
+		if rv.IsNil() {
+			t.Fatalf("unexpected nil")
+		}
+	}
+}
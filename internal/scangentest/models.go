@@ -0,0 +1,57 @@
+// Package scangentest holds the types used to check that scanners generated
+// by cmd/scangen fill structs exactly like the reflective path.
+package scangentest
+
+import "time"
+
+//go:generate go run ../../cmd/scangen -type User,Order,Report,Join
+
+type Base struct {
+	ID        int64     `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type Address struct {
+	City string `db:"city"`
+	Zip  string
+}
+
+type audit struct {
+	Editor string
+}
+
+type User struct {
+	Base
+	audit
+	Name     string
+	Email    string `db:"email_address"`
+	Score    float64
+	Home     Address
+	Tags     []string  `db:"tags,split"`
+	Joined   time.Time `db:"joined,unix"`
+	Nickname *string
+	secret   string
+}
+
+type Order struct {
+	*Base
+	Number string `db:"order_number"`
+	Total  float64
+	Extras map[string]string `db:",extra"`
+}
+
+type Report struct {
+	Title string
+	Stats struct {
+		Count int `db:"count"`
+		Sum   float64
+	}
+}
+
+type Join struct {
+	ID      int64  `db:"id"`
+	OtherID int64  `db:"id#2"`
+	Third   string `db:"@3"`
+	Skipped string `db:"-"`
+	Name    string
+}
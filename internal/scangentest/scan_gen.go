@@ -0,0 +1,319 @@
+// Code generated by scangen; DO NOT EDIT.
+
+package scangentest
+
+import (
+	"time"
+
+	"github.com/goapt/scan"
+)
+
+func init() {
+	scan.RegisterScanner(prepareUser)
+	scan.RegisterScanner(prepareOrder)
+	scan.RegisterScanner(prepareReport)
+	scan.RegisterScanner(prepareJoin)
+}
+
+// fieldOfUser returns the field of User that the column at index i is
+// scanned into, or -1. col is its name and n counts the columns so named.
+func fieldOfUser(i int, col string, n int) int {
+	switch col {
+	case "city":
+		return 3
+	case "created_at":
+		return 1
+	case "email_address":
+		return 2
+	case "id":
+		return 0
+	case "joined":
+		return 5
+	case "tags":
+		return 4
+	}
+	switch scan.ScannerMapper(col) {
+	case "Base":
+		return 6
+	case "CreatedAt":
+		return 1
+	case "Editor":
+		return 13
+	case "Email":
+		return 2
+	case "Home":
+		return 10
+	case "ID":
+		return 0
+	case "Joined":
+		return 5
+	case "Name":
+		return 8
+	case "Nickname":
+		return 11
+	case "Score":
+		return 9
+	case "Tags":
+		return 4
+	}
+	return -1
+}
+
+// prepareUser returns the r.Scan destinations of cols for User and a
+// function binding them to the fields of each row.
+func prepareUser(sc *scan.Scanner, cols []string) ([]any, func(*User)) {
+	dests := make([]any, len(cols))
+	binds := make([]func(*User), 0, len(cols))
+	for i, col := range cols {
+		switch fieldOfUser(i, col, 0) {
+		case 0:
+			d := scan.NewField[int64](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *User) { d.Bind(&v.Base.ID) })
+		case 1:
+			d := scan.NewField[time.Time](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *User) { d.Bind(&v.Base.CreatedAt) })
+		case 2:
+			d := scan.NewField[string](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *User) { d.Bind(&v.Email) })
+		case 3:
+			d := scan.NewField[string](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *User) { d.Bind(&v.Home.City) })
+		case 4:
+			d := scan.NewField[[]string](sc, col, "tags,split")
+			dests[i] = d
+			binds = append(binds, func(v *User) { d.Bind(&v.Tags) })
+		case 5:
+			d := scan.NewField[time.Time](sc, col, "joined,unix")
+			dests[i] = d
+			binds = append(binds, func(v *User) { d.Bind(&v.Joined) })
+		case 6:
+			d := scan.NewField[Base](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *User) { d.Bind(&v.Base) })
+		case 8:
+			d := scan.NewField[string](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *User) { d.Bind(&v.Name) })
+		case 9:
+			d := scan.NewField[float64](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *User) { d.Bind(&v.Score) })
+		case 10:
+			d := scan.NewField[Address](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *User) { d.Bind(&v.Home) })
+		case 11:
+			d := scan.NewField[*string](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *User) { d.Bind(&v.Nickname) })
+		case 13:
+			d := scan.NewField[string](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *User) { d.Bind(&v.audit.Editor) })
+		default:
+			dests[i] = new(any)
+		}
+	}
+	return dests, func(v *User) {
+		for _, bind := range binds {
+			bind(v)
+		}
+	}
+}
+
+// fieldOfOrder returns the field of Order that the column at index i is
+// scanned into, or -1. col is its name and n counts the columns so named.
+func fieldOfOrder(i int, col string, n int) int {
+	switch col {
+	case "created_at":
+		return 1
+	case "id":
+		return 0
+	case "order_number":
+		return 2
+	}
+	switch scan.ScannerMapper(col) {
+	case "Base":
+		return 3
+	case "CreatedAt":
+		return 1
+	case "ID":
+		return 0
+	case "Number":
+		return 2
+	case "Total":
+		return 4
+	}
+	return -1
+}
+
+// prepareOrder returns the r.Scan destinations of cols for Order and a
+// function binding them to the fields of each row.
+func prepareOrder(sc *scan.Scanner, cols []string) ([]any, func(*Order)) {
+	dests := make([]any, len(cols))
+	binds := make([]func(*Order), 0, len(cols))
+	for i, col := range cols {
+		switch fieldOfOrder(i, col, 0) {
+		case 0:
+			d := scan.NewField[int64](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *Order) {
+				if v.Base == nil {
+					v.Base = new(Base)
+				}
+				d.Bind(&v.Base.ID)
+			})
+		case 1:
+			d := scan.NewField[time.Time](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *Order) {
+				if v.Base == nil {
+					v.Base = new(Base)
+				}
+				d.Bind(&v.Base.CreatedAt)
+			})
+		case 2:
+			d := scan.NewField[string](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *Order) { d.Bind(&v.Number) })
+		case 3:
+			d := scan.NewField[*Base](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *Order) { d.Bind(&v.Base) })
+		case 4:
+			d := scan.NewField[float64](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *Order) { d.Bind(&v.Total) })
+		default:
+			d := scan.NewExtra[map[string]string](sc, col)
+			dests[i] = d
+			binds = append(binds, func(v *Order) { d.Bind(&v.Extras) })
+		}
+	}
+	return dests, func(v *Order) {
+		for _, bind := range binds {
+			bind(v)
+		}
+	}
+}
+
+// fieldOfReport returns the field of Report that the column at index i is
+// scanned into, or -1. col is its name and n counts the columns so named.
+func fieldOfReport(i int, col string, n int) int {
+	switch col {
+	case "count":
+		return 0
+	}
+	switch scan.ScannerMapper(col) {
+	case "Stats":
+		return 2
+	case "Title":
+		return 1
+	}
+	return -1
+}
+
+// prepareReport returns the r.Scan destinations of cols for Report and a
+// function binding them to the fields of each row.
+func prepareReport(sc *scan.Scanner, cols []string) ([]any, func(*Report)) {
+	dests := make([]any, len(cols))
+	binds := make([]func(*Report), 0, len(cols))
+	for i, col := range cols {
+		switch fieldOfReport(i, col, 0) {
+		case 0:
+			d := scan.NewField[int](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *Report) { d.Bind(&v.Stats.Count) })
+		case 1:
+			d := scan.NewField[string](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *Report) { d.Bind(&v.Title) })
+		case 2:
+			d := scan.NewField[struct {
+				Count int "db:\"count\""
+				Sum   float64
+			}](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *Report) { d.Bind(&v.Stats) })
+		default:
+			dests[i] = new(any)
+		}
+	}
+	return dests, func(v *Report) {
+		for _, bind := range binds {
+			bind(v)
+		}
+	}
+}
+
+// fieldOfJoin returns the field of Join that the column at index i is
+// scanned into, or -1. col is its name and n counts the columns so named.
+func fieldOfJoin(i int, col string, n int) int {
+	switch i {
+	case 2:
+		return 2
+	}
+	switch {
+	case n == 2 && col == "id":
+		return 1
+	}
+	switch col {
+	case "@3":
+		return 2
+	case "id":
+		return 0
+	case "id#2":
+		return 1
+	}
+	switch scan.ScannerMapper(col) {
+	case "ID":
+		return 0
+	case "Name":
+		return 3
+	case "OtherID":
+		return 1
+	case "Third":
+		return 2
+	}
+	return -1
+}
+
+// prepareJoin returns the r.Scan destinations of cols for Join and a
+// function binding them to the fields of each row.
+func prepareJoin(sc *scan.Scanner, cols []string) ([]any, func(*Join)) {
+	dests := make([]any, len(cols))
+	binds := make([]func(*Join), 0, len(cols))
+	seen := make(map[string]int, len(cols))
+	for i, col := range cols {
+		seen[col]++
+		switch fieldOfJoin(i, col, seen[col]) {
+		case 0:
+			d := scan.NewField[int64](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *Join) { d.Bind(&v.ID) })
+		case 1:
+			d := scan.NewField[int64](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *Join) { d.Bind(&v.OtherID) })
+		case 2:
+			d := scan.NewField[string](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *Join) { d.Bind(&v.Third) })
+		case 3:
+			d := scan.NewField[string](sc, col, "")
+			dests[i] = d
+			binds = append(binds, func(v *Join) { d.Bind(&v.Name) })
+		default:
+			dests[i] = new(any)
+		}
+	}
+	return dests, func(v *Join) {
+		for _, bind := range binds {
+			bind(v)
+		}
+	}
+}
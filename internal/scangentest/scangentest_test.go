@@ -0,0 +1,188 @@
+package scangentest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/goapt/scan"
+	"github.com/goapt/scan/internal/assert"
+	"github.com/goapt/scan/internal/fakedb"
+	"github.com/goapt/scan/internal/require"
+)
+
+var (
+	testDB *sql.DB
+	once   sync.Once
+	dsn    = "root:123456@tcp(127.0.0.1:3306)/test?charset=utf8&parseTime=True&loc=Asia%2FShanghai"
+)
+
+func db(t testing.TB) *sql.DB {
+	once.Do(func() {
+		var err error
+		testDB, err = sql.Open("mysql", dsn)
+		if err != nil {
+			t.Skip(err.Error())
+			return
+		}
+		if err := testDB.Ping(); err != nil {
+			t.Skip(err.Error())
+			return
+		}
+	})
+	return testDB
+}
+
+// The plain types share the layout of the generated ones but have no
+// registered scanner, so Rows fills them through reflection.
+type (
+	plainUser   User
+	plainOrder  Order
+	plainReport Report
+	plainJoin   Join
+)
+
+// compare scans query into G, which has a generated scanner, and into P,
+// which does not, and checks both give the same rows or the same error.
+func compare[G, P any](t *testing.T, conv func(P) G, query string) {
+	t.Helper()
+	rows, err := db(t).Query(query)
+	require.NoError(t, err)
+	generated, genErr := scan.Rows[G](rows)
+
+	rows, err = db(t).Query(query)
+	require.NoError(t, err)
+	plain, plainErr := scan.Rows[P](rows)
+
+	if genErr != nil || plainErr != nil {
+		require.Error(t, genErr)
+		require.Error(t, plainErr)
+		assert.Equal(t, plainErr.Error(), genErr.Error())
+		return
+	}
+	require.Len(t, generated, len(plain))
+	for i := range plain {
+		assert.Equal(t, conv(plain[i]), generated[i])
+	}
+}
+
+func TestGeneratedMatchesReflection(t *testing.T) {
+	user := func(p plainUser) User { return User(p) }
+	order := func(p plainOrder) Order { return Order(p) }
+	report := func(p plainReport) Report { return Report(p) }
+	join := func(p plainJoin) Join { return Join(p) }
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T)
+	}{
+		{"tags and promoted fields", func(t *testing.T) {
+			compare(t, user, "SELECT 1 AS id, '2024-01-02 03:04:05' AS created_at, 'ann' AS Editor, 'Ann' AS name, 'a@example.com' AS email_address, 1.5 AS score")
+		}},
+		{"nested struct tags", func(t *testing.T) {
+			compare(t, user, "SELECT 'Paris' AS city, 'x' AS zip, 'y' AS home")
+		}},
+		{"tag options", func(t *testing.T) {
+			compare(t, user, "SELECT 'a,b' AS tags, 1700000000 AS joined")
+		}},
+		{"null and pointer fields", func(t *testing.T) {
+			compare(t, user, "SELECT NULL AS name, 'nick' AS nickname UNION ALL SELECT 'Bob', NULL")
+		}},
+		{"unexported and unknown columns", func(t *testing.T) {
+			compare(t, user, "SELECT 's' AS secret, 'u' AS unknown, 'n' AS name")
+		}},
+		{"conversion error", func(t *testing.T) {
+			compare(t, user, "SELECT 'abc' AS score")
+		}},
+		{"embedded pointer and extras", func(t *testing.T) {
+			compare(t, order, "SELECT 7 AS id, 'A-1' AS order_number, 9.5 AS total, 'v' AS note")
+		}},
+		{"extras without embedded pointer", func(t *testing.T) {
+			compare(t, order, "SELECT 'A-2' AS order_number, 'w' AS note")
+		}},
+		{"pointer elements", func(t *testing.T) {
+			compare(t, func(p *plainUser) *User { return (*User)(p) }, "SELECT 1 AS id, 'Ann' AS name UNION ALL SELECT 2, 'Bob'")
+		}},
+		{"anonymous struct field", func(t *testing.T) {
+			compare(t, report, "SELECT 't' AS title, 3 AS count, 1.5 AS sum")
+		}},
+		{"ordinal, occurrence and ignored tags", func(t *testing.T) {
+			compare(t, join, "SELECT 1 AS id, 2 AS id, 'x' AS other, 'n' AS name, 's' AS skipped, 3 AS id")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
+
+func TestGeneratedEmbeddedPointerTags(t *testing.T) {
+	rows, err := db(t).Query("SELECT 7 AS id, 'v' AS note")
+	require.NoError(t, err)
+	orders, err := scan.Rows[Order](rows)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	require.NotNil(t, orders[0].Base)
+	assert.Equal(t, int64(7), orders[0].ID)
+	assert.Equal(t, map[string]string{"note": "v"}, orders[0].Extras)
+}
+
+func TestGeneratedKeyedRows(t *testing.T) {
+	const query = "SELECT 1 AS id, 'Ann' AS name UNION ALL SELECT 2, 'Bob'"
+	rows, err := db(t).Query(query)
+	require.NoError(t, err)
+	generated, err := scan.Map[int64, User](rows, "id")
+	require.NoError(t, err)
+
+	rows, err = db(t).Query(query)
+	require.NoError(t, err)
+	plain, err := scan.Map[int64, plainUser](rows, "id")
+	require.NoError(t, err)
+
+	require.Len(t, generated, len(plain))
+	for k, p := range plain {
+		assert.Equal(t, User(p), generated[k])
+	}
+}
+
+func TestGeneratedScannerSkippedForMappingOptions(t *testing.T) {
+	// Only WithIgnoreCase maps EMAIL_ADDRESS to `db:"email_address"`.
+	rows, err := db(t).Query("SELECT 'a@example.com' AS EMAIL_ADDRESS")
+	require.NoError(t, err)
+	users, err := scan.Rows[User](rows, scan.WithIgnoreCase())
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "a@example.com", users[0].Email)
+}
+
+// benchDB returns 100 rows scanned into User from memory.
+func benchDB() *sql.DB {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	values := make([][]driver.Value, 100)
+	for i := range values {
+		values[i] = []driver.Value{int64(i), created, []byte("name"), []byte("name@example.com"), 1.5}
+	}
+	return fakedb.Open([]string{"id", "created_at", "name", "email_address", "score"}, values)
+}
+
+func BenchmarkRowsGenerated(b *testing.B) {
+	benchmarkRows[User](b)
+}
+
+func BenchmarkRowsReflect(b *testing.B) {
+	benchmarkRows[plainUser](b)
+}
+
+func benchmarkRows[T any](b *testing.B) {
+	db := benchDB()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rows := fakedb.Query(b, db)
+		if _, err := scan.Rows[T](rows); err != nil {
+			b.Fatal(err)
+		}
+		rows.Close()
+	}
+}
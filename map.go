@@ -0,0 +1,112 @@
+package scan
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Map scans sql rows into a map of T keyed by the value of keyColumn,
+// converted into K like any other column. The key column may also be mapped
+// into T. When T is a primitive type, its value is read from the column
+// that is not the key, so SELECT id, name can be scanned into map[int64]string.
+// Two rows with the same key make Map fail with ErrDuplicateKey, and a NULL
+// key fails with ErrNullKey unless K is a pointer or implements sql.Scanner
+// through its pointer, as the sql.Null types do.
+func Map[K comparable, T any](r *sql.Rows, keyColumn string, opts ...Option) (map[K]T, error) {
+	out := make(map[K]T)
+	err := scanKeyed(r, keyColumn, newScanner(opts), func(key K, item T) error {
+		if _, ok := out[key]; ok {
+			return fmt.Errorf("%w %v in column %q", ErrDuplicateKey, key, keyColumn)
+		}
+		out[key] = item
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MapSlice is like Map, but groups the rows sharing a key into a slice, in
+// the order of the rows.
+func MapSlice[K comparable, T any](r *sql.Rows, keyColumn string, opts ...Option) (map[K][]T, error) {
+	out := make(map[K][]T)
+	err := scanKeyed(r, keyColumn, newScanner(opts), func(key K, item T) error {
+		out[key] = append(out[key], item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// scanKeyed scans each row into a T and passes it to add with its key.
+func scanKeyed[K comparable, T any](r *sql.Rows, column string, sc *Scanner, add func(K, T) error) error {
+	var key K
+	var item T
+	kc := &keyColumn{name: column, dest: &key, nullable: acceptsNull[K](), added: func() error {
+		return add(key, item)
+	}}
+	return scanRows(r, sc, kc, func() *T {
+		key, item = *new(K), *new(T)
+		return &item
+	})
+}
+
+// acceptsNull reports whether NULL can be scanned into a K without losing
+// the difference from a zero value.
+func acceptsNull[K any]() bool {
+	t := reflect.TypeFor[K]()
+	return t.Kind() == reflect.Pointer || reflect.PointerTo(t).Implements(sqlScannerType)
+}
+
+// keyColumn captures the value of a column, converted into dest, while the
+// rows are scanned.
+type keyColumn struct {
+	name  string
+	index int
+	dest  any
+	// nullable is set when dest accepts NULL.
+	nullable bool
+	// added is called after each row has been scanned.
+	added func() error
+}
+
+// scan scans the current row into pointers, capturing the key column when
+// kc is not nil.
+func (kc *keyColumn) scan(r *sql.Rows, sc *Scanner, pointers []any) error {
+	if kc == nil {
+		return r.Scan(pointers...)
+	}
+	dest := pointers[kc.index]
+	pointers[kc.index] = keyScanner{dest: dest, column: kc, scanner: sc}
+	err := r.Scan(pointers...)
+	// Generated scanners reuse pointers for every row.
+	pointers[kc.index] = dest
+	if err != nil {
+		return err
+	}
+	return kc.added()
+}
+
+// keyScanner converts a value into the key and then scans it into dest.
+type keyScanner struct {
+	dest    any
+	column  *keyColumn
+	scanner *Scanner
+}
+
+func (k keyScanner) Scan(src any) error {
+	if src == nil && !k.column.nullable {
+		return fmt.Errorf("%w in column %q", ErrNullKey, k.column.name)
+	}
+	if err := k.scanner.convertAssign(k.column.dest, src); err != nil {
+		return err
+	}
+	if s, ok := k.dest.(sql.Scanner); ok {
+		return s.Scan(src)
+	}
+	return k.scanner.convertAssign(k.dest, src)
+}
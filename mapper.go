@@ -0,0 +1,145 @@
+package scan
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// WithMapper matches columns to fields by the names mapper returns. Both the
+// column names and the Go field names are passed through mapper, and a column
+// maps to the field whose name maps to the same string, e.g. with
+// CamelToSnake the column user_id maps to the field UserID.
+//
+// Without this option columns are matched by ScannerMapper alone.
+// Tagged fields take precedence in both cases.
+func WithMapper(mapper func(name string) string) Option {
+	return func(sc *Scanner) {
+		sc.mapper = mapper
+	}
+}
+
+// commonInitialisms are the initialisms golint expects in upper case.
+var commonInitialisms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP",
+	"HTTPS", "ID", "IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC", "SLA",
+	"SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID", "UUID",
+	"URI", "URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS",
+}
+
+var snakeToGo = NewSnakeToGo()
+
+// SnakeToGo maps a snake_case name to an idiomatic Go name, writing the
+// initialisms golint knows in upper case: user_id becomes UserID and
+// api_url becomes APIURL.
+func SnakeToGo(name string) string {
+	return snakeToGo(name)
+}
+
+// NewSnakeToGo returns a mapper like SnakeToGo that also writes the given
+// initialisms in upper case.
+func NewSnakeToGo(initialisms ...string) func(name string) string {
+	set := make(map[string]bool, len(commonInitialisms)+len(initialisms))
+	for _, s := range commonInitialisms {
+		set[s] = true
+	}
+	for _, s := range initialisms {
+		set[strings.ToUpper(s)] = true
+	}
+	return func(name string) string {
+		parts := strings.Split(name, "_")
+		for i, part := range parts {
+			if upper := strings.ToUpper(part); set[upper] {
+				parts[i] = upper
+			} else {
+				parts[i] = capitalizeFirst(part)
+			}
+		}
+		return strings.Join(parts, "")
+	}
+}
+
+// CaseInsensitive maps names like the default ScannerMapper, ignoring case,
+// so user_id matches both UserId and UserID.
+func CaseInsensitive(name string) string {
+	return strings.ToLower(toTitleCase(name))
+}
+
+// Lower maps names to lower case, so userid and USERID match UserID.
+func Lower(name string) string {
+	return strings.ToLower(name)
+}
+
+// CamelToSnake maps a CamelCase name to snake_case, keeping initialisms
+// together: UserID becomes user_id and HTTPServer becomes http_server.
+// Names already in snake_case are kept.
+func CamelToSnake(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prev != '_' && (unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// mappedFields indexes the fields of typ that FieldByName can find by the
+// names mapper returns for them. Like FieldByName, a shallower field hides
+// deeper ones, and names matching several fields at the same depth match none.
+// Fields for which ignored reports true still hide deeper ones but match nothing.
+func mappedFields(typ reflect.Type, mapper func(string) string, ignored func(index []int) bool) map[string][]int {
+	index := make(map[string][]int)
+	hidden := make(map[string]bool)
+
+	type level struct {
+		typ   reflect.Type
+		index []int
+	}
+	current := []level{{typ: typ}}
+	visited := make(map[reflect.Type]bool)
+	for len(current) > 0 {
+		var next []level
+		found := make(map[string][]int)
+		ambiguous := make(map[string]bool)
+		for _, l := range current {
+			if visited[l.typ] {
+				continue
+			}
+			visited[l.typ] = true
+			for i := 0; i < l.typ.NumField(); i++ {
+				sf := l.typ.Field(i)
+				fieldIndex := append(append([]int(nil), l.index...), i)
+				key := mapper(sf.Name)
+				if !hidden[key] {
+					if _, dup := found[key]; dup {
+						ambiguous[key] = true
+					}
+					found[key] = fieldIndex
+				}
+				if sf.Anonymous {
+					ft := sf.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						next = append(next, level{typ: ft, index: fieldIndex})
+					}
+				}
+			}
+		}
+		for key, fieldIndex := range found {
+			if !ambiguous[key] && !ignored(fieldIndex) {
+				index[key] = fieldIndex
+			}
+			hidden[key] = true
+		}
+		current = next
+	}
+	return index
+}
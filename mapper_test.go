@@ -0,0 +1,224 @@
+package scan
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMappers(t *testing.T) {
+	tests := []struct {
+		name   string
+		mapper func(string) string
+		in     string
+		want   string
+	}{
+		{"SnakeToGo", SnakeToGo, "user_id", "UserID"},
+		{"SnakeToGo", SnakeToGo, "api_url", "APIURL"},
+		{"SnakeToGo", SnakeToGo, "first_name", "FirstName"},
+		{"SnakeToGo", SnakeToGo, "UserID", "UserID"},
+		{"SnakeToGo", SnakeToGo, "Id", "ID"},
+		{"SnakeToGo", SnakeToGo, "sku_code", "SkuCode"},
+		{"NewSnakeToGo", NewSnakeToGo("sku"), "sku_code", "SKUCode"},
+		{"NewSnakeToGo", NewSnakeToGo("sku"), "user_id", "UserID"},
+		{"CaseInsensitive", CaseInsensitive, "user_id", "userid"},
+		{"CaseInsensitive", CaseInsensitive, "UserID", "userid"},
+		{"Lower", Lower, "USERID", "userid"},
+		{"Lower", Lower, "user_id", "user_id"},
+		{"CamelToSnake", CamelToSnake, "UserID", "user_id"},
+		{"CamelToSnake", CamelToSnake, "HTTPServer", "http_server"},
+		{"CamelToSnake", CamelToSnake, "ID", "id"},
+		{"CamelToSnake", CamelToSnake, "Utf8Name", "utf8_name"},
+		{"CamelToSnake", CamelToSnake, "user_id", "user_id"},
+		{"CamelToSnake", CamelToSnake, "Created_At", "created_at"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.in, func(t *testing.T) {
+			if got := tt.mapper(tt.in); got != tt.want {
+				t.Errorf("%s(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMappedFields(t *testing.T) {
+	type Inner struct {
+		UserID int
+		Name   string
+		Shared string
+	}
+	type Other struct {
+		Shared string
+	}
+	type Outer struct {
+		Inner
+		*Other
+		Name  string
+		Email string
+	}
+	got := mappedFields(reflect.TypeFor[Outer](), CamelToSnake, func([]int) bool { return false })
+	want := map[string][]int{
+		"inner":   {0},
+		"other":   {1},
+		"name":    {2},
+		"email":   {3},
+		"user_id": {0, 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mappedFields() = %v, want %v", got, want)
+	}
+}
+
+func TestNewStructMappingWithMapper(t *testing.T) {
+	type User struct {
+		UserID  int
+		APIKey  string
+		Profile string `db:"bio"`
+	}
+	sc := newScanner([]Option{WithMapper(SnakeToGo)})
+	m, err := newStructMapping(sc, reflect.TypeFor[User](), []string{"user_id", "api_key", "bio", "profile", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []field{
+		{index: []int{0}, match: MatchMapper},
+		{index: []int{1}, match: MatchMapper},
+		{index: []int{2}, tag: fieldTag{name: "bio"}, match: MatchTag},
+		{index: []int{2}, tag: fieldTag{name: "bio"}, match: MatchMapper},
+		{},
+	}
+	if !reflect.DeepEqual(m.fields, want) {
+		t.Errorf("fields = %+v, want %+v", m.fields, want)
+	}
+}
+
+func TestNewStructMappingIgnoreCase(t *testing.T) {
+	type User struct {
+		ID       int `db:"id"`
+		UserName string
+	}
+	sc := newScanner([]Option{WithIgnoreCase()})
+	m, err := newStructMapping(sc, reflect.TypeFor[User](), []string{"ID", "USER_NAME", "username"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []field{
+		{index: []int{0}, tag: fieldTag{name: "id"}, match: MatchTag},
+		{index: []int{1}, match: MatchMapper},
+		{index: []int{1}, match: MatchMapper},
+	}
+	if !reflect.DeepEqual(m.fields, want) {
+		t.Errorf("fields = %+v, want %+v", m.fields, want)
+	}
+}
+
+func TestNewStructMappingTableQualifiers(t *testing.T) {
+	type User struct {
+		Name  string
+		Email string `db:"email"`
+	}
+	type Order struct {
+		ID     int    `db:"id"`
+		Status string `db:"orders.status"`
+		User   User
+		Owner  *User `db:"owner"`
+	}
+	cols := []string{"orders.id", "users.name", "users.email", "owner.email", "orders.status", "app.orders.id", "items.sku"}
+	qualified := []field{
+		{index: []int{0}, tag: fieldTag{name: "id"}, match: MatchAlias},
+		{index: []int{2, 0}, match: MatchAlias},
+		{index: []int{2, 1}, tag: fieldTag{name: "email"}, match: MatchAlias},
+		{index: []int{3, 1}, tag: fieldTag{name: "email"}, match: MatchAlias},
+		{index: []int{1}, tag: fieldTag{name: "orders.status"}, match: MatchTag},
+		{index: []int{0}, tag: fieldTag{name: "id"}, match: MatchAlias},
+		{},
+	}
+	tests := []struct {
+		name string
+		cols []string
+		opts []Option
+		want []field
+	}{
+		{
+			name: "qualifiers",
+			cols: cols,
+			opts: []Option{WithTableQualifiers()},
+			want: qualified,
+		},
+		{
+			name: "qualifiers ignoring case",
+			cols: []string{"ORDERS.ID", "Users.Name", "USERS.EMAIL", "Owner.Email", "Orders.Status", "APP.ORDERS.ID", "ITEMS.SKU"},
+			opts: []Option{WithTableQualifiers(), WithIgnoreCase()},
+			want: qualified,
+		},
+		{
+			name: "without qualifiers",
+			cols: cols,
+			want: []field{{}, {}, {}, {}, {index: []int{1}, tag: fieldTag{name: "orders.status"}, match: MatchTag}, {}, {}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := newStructMapping(newScanner(tt.opts), reflect.TypeFor[Order](), tt.cols)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(m.fields, tt.want) {
+				t.Errorf("fields = %+v, want %+v", m.fields, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewStructMappingDuplicateColumns(t *testing.T) {
+	type Join struct {
+		UserID  int    `db:"id"`
+		OrderID int    `db:"id#2"`
+		Third   string `db:"@3"`
+		Name    string
+	}
+	cols := []string{"id", "id", "name", "name"}
+	m, err := newStructMapping(defaultScanner, reflect.TypeFor[Join](), cols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []field{
+		{index: []int{0}, tag: fieldTag{name: "id"}, match: MatchTag},
+		{index: []int{1}, tag: fieldTag{name: "id#2"}, match: MatchTag},
+		{index: []int{2}, tag: fieldTag{name: "@3"}, match: MatchTag},
+		{index: []int{3}, match: MatchMapper},
+	}
+	if !reflect.DeepEqual(m.fields, want) {
+		t.Errorf("fields = %+v, want %+v", m.fields, want)
+	}
+
+	strict := newScanner([]Option{WithStrictColumns()})
+	if _, err := newStructMapping(strict, reflect.TypeFor[Join](), cols); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	type Plain struct {
+		ID     int            `db:"id"`
+		Extras map[string]any `db:",extra"`
+	}
+	tests := []struct {
+		cols []string
+		err  string
+	}{
+		{[]string{"id", "name", "id"}, `duplicate column: columns 1 ("id") and 3 ("id") are both scanned into ID`},
+		{[]string{"id", "note", "note"}, `duplicate column: columns 2 ("note") and 3 ("note") are both scanned into Extras["note"]`},
+		{[]string{"id", "note"}, ""},
+	}
+	for _, tt := range tests {
+		_, err := newStructMapping(strict, reflect.TypeFor[Plain](), tt.cols)
+		if tt.err == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error: %v", tt.cols, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrDuplicateColumn) || err.Error() != tt.err {
+			t.Errorf("%q: error = %v, want %s", tt.cols, err, tt.err)
+		}
+	}
+}
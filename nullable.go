@@ -3,16 +3,21 @@ package scan
 import (
 	"database/sql"
 	"reflect"
+	"time"
 )
 
 type nullable struct {
-	dest any
+	dest    any
+	scanner *Scanner
 }
 
 var _ sql.Scanner = (*nullable)(nil)
 
 func (n nullable) Scan(src any) error {
-	return convertAssign(n.dest, src)
+	if n.scanner == nil {
+		return convertAssign(n.dest, src)
+	}
+	return n.scanner.convertAssign(n.dest, src)
 }
 
 // Nullable wrap value as a nullable sql.Scanner.
@@ -35,3 +40,23 @@ func Nullable(dest any) any {
 		dest: dest,
 	}
 }
+
+// nullable is like Nullable, but pointer and sql.Null time and bool
+// destinations are also converted by sc so that its settings apply to them.
+func (sc *Scanner) nullable(dest any) sql.Scanner {
+	if s, ok := selfScanner(dest); ok {
+		return s
+	}
+	return nullable{dest: dest, scanner: sc}
+}
+
+// selfScanner returns dest when it is an sql.Scanner that converts values
+// itself. The sql.Null time and bool wrappers are left to the Scanner.
+func selfScanner(dest any) (sql.Scanner, bool) {
+	switch dest.(type) {
+	case *sql.NullTime, *sql.Null[time.Time], *sql.NullBool, *sql.Null[bool]:
+		return nil, false
+	}
+	s, ok := dest.(sql.Scanner)
+	return s, ok
+}
@@ -0,0 +1,117 @@
+package scan
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// NumericPolicy controls how values are converted into int, uint and float destinations.
+type NumericPolicy int
+
+const (
+	// NumericStrict parses the source exactly as strconv would. Integers must
+	// not have a fraction or exponent, floats are rounded to fit. This is the default.
+	NumericStrict NumericPolicy = iota
+	// NumericLenient trims surrounding whitespace and accepts integral floats
+	// and exponent forms such as "3.0" or "1e3" for integer destinations.
+	NumericLenient
+	// NumericLossless parses like NumericLenient but rejects any conversion
+	// that would round or truncate the value, including into floats.
+	NumericLossless
+)
+
+// WithNumericPolicy sets the policy used for numeric conversions.
+func WithNumericPolicy(p NumericPolicy) Option {
+	return func(sc *Scanner) {
+		sc.numeric = p
+	}
+}
+
+func (sc *Scanner) parseInt(s string, bitSize int) (int64, error) {
+	if sc.numeric == NumericStrict {
+		return strconv.ParseInt(s, 10, bitSize)
+	}
+	s = strings.TrimSpace(s)
+	i64, err := strconv.ParseInt(s, 10, bitSize)
+	if !errors.Is(err, strconv.ErrSyntax) {
+		return i64, err
+	}
+	n, ierr := parseIntegral(s)
+	if ierr != nil {
+		return 0, ierr
+	}
+	if !n.IsInt64() {
+		return 0, strconv.ErrRange
+	}
+	i64 = n.Int64()
+	if rest := i64 >> (bitSize - 1); rest != 0 && rest != -1 {
+		return 0, strconv.ErrRange
+	}
+	return i64, nil
+}
+
+func (sc *Scanner) parseUint(s string, bitSize int) (uint64, error) {
+	if sc.numeric == NumericStrict {
+		return strconv.ParseUint(s, 10, bitSize)
+	}
+	s = strings.TrimSpace(s)
+	u64, err := strconv.ParseUint(s, 10, bitSize)
+	if !errors.Is(err, strconv.ErrSyntax) {
+		return u64, err
+	}
+	n, ierr := parseIntegral(s)
+	if ierr != nil {
+		return 0, ierr
+	}
+	if n.Sign() < 0 || n.BitLen() > bitSize {
+		return 0, strconv.ErrRange
+	}
+	return n.Uint64(), nil
+}
+
+func (sc *Scanner) parseFloat(src any, s string, bitSize int) (float64, error) {
+	if sc.numeric != NumericStrict {
+		s = strings.TrimSpace(s)
+	}
+	f64, err := strconv.ParseFloat(s, bitSize)
+	if err != nil {
+		return f64, err
+	}
+
+	if sc.numeric == NumericLossless && !floatFits(src, s, f64, bitSize) {
+		return 0, errLosesPrecision
+	}
+	return f64, nil
+}
+
+// parseIntegral parses a decimal number with an optional fraction or
+// exponent, failing unless its value is a whole number.
+func parseIntegral(s string) (*big.Int, error) {
+	if s == "" || strings.ContainsFunc(s, func(r rune) bool {
+		return !strings.ContainsRune("0123456789+-.eE", r)
+	}) {
+		return nil, strconv.ErrSyntax
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, strconv.ErrSyntax
+	}
+	if !r.IsInt() {
+		return nil, errLosesPrecision
+	}
+	return r.Num(), nil
+}
+
+// floatFits reports whether f, parsed from src, holds src without rounding.
+func floatFits(src any, s string, f float64, bitSize int) bool {
+	switch v := src.(type) {
+	case float64:
+		return bitSize == 64 || math.IsNaN(v) || float64(float32(v)) == v
+	case float32:
+		return true
+	}
+	return decimalFits(s, f, bitSize)
+}
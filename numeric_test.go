@@ -0,0 +1,76 @@
+package scan
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestNumericPolicy(t *testing.T) {
+	var (
+		i8  int8
+		i64 int64
+		u8  uint8
+		f32 float32
+		f64 float64
+	)
+	tests := []struct {
+		policy  NumericPolicy
+		s, d    any
+		want    any
+		wanterr string
+	}{
+		{policy: NumericStrict, s: float64(3), d: &i64, want: int64(3)},
+		{policy: NumericStrict, s: "3.0", d: &i64, wanterr: `converting driver.Value type string ("3.0") to a int64: invalid syntax`},
+		{policy: NumericStrict, s: " 3", d: &i64, wanterr: `converting driver.Value type string (" 3") to a int64: invalid syntax`},
+		{policy: NumericStrict, s: float64(0.1), d: &f32, want: float32(0.1)},
+		{policy: NumericStrict, s: "16777217", d: &f32, want: float32(16777216)},
+
+		{policy: NumericLenient, s: "3.0", d: &i64, want: int64(3)},
+		{policy: NumericLenient, s: []byte(" 42 "), d: &i64, want: int64(42)},
+		{policy: NumericLenient, s: "1e3", d: &i64, want: int64(1000)},
+		{policy: NumericLenient, s: float64(1e15), d: &i64, want: int64(1e15)},
+		{policy: NumericLenient, s: "-128.0", d: &i8, want: int8(-128)},
+		{policy: NumericLenient, s: "127.0", d: &i8, want: int8(127)},
+		{policy: NumericLenient, s: "128.0", d: &i8, wanterr: `converting driver.Value type string ("128.0") to a int8: value out of range`},
+		{policy: NumericLenient, s: "-129", d: &i8, wanterr: `converting driver.Value type string ("-129") to a int8: value out of range`},
+		{policy: NumericLenient, s: "1e19", d: &i64, wanterr: `converting driver.Value type string ("1e19") to a int64: value out of range`},
+		{policy: NumericLenient, s: 3.5, d: &i64, wanterr: `converting driver.Value type float64 ("3.5") to a int64: value loses precision`},
+		{policy: NumericLenient, s: "0x10", d: &i64, wanterr: `converting driver.Value type string ("0x10") to a int64: invalid syntax`},
+		{policy: NumericLenient, s: "6/2", d: &i64, wanterr: `converting driver.Value type string ("6/2") to a int64: invalid syntax`},
+		{policy: NumericLenient, s: "255.0", d: &u8, want: uint8(255)},
+		{policy: NumericLenient, s: "2.55e2", d: &u8, want: uint8(255)},
+		{policy: NumericLenient, s: "256.0", d: &u8, wanterr: `converting driver.Value type string ("256.0") to a uint8: value out of range`},
+		{policy: NumericLenient, s: "-1.0", d: &u8, wanterr: `converting driver.Value type string ("-1.0") to a uint8: value out of range`},
+		{policy: NumericLenient, s: " 1.5 ", d: &f64, want: 1.5},
+		{policy: NumericLenient, s: "16777217", d: &f32, want: float32(16777216)},
+
+		{policy: NumericLossless, s: "3.0", d: &i64, want: int64(3)},
+		{policy: NumericLossless, s: "3.5", d: &i64, wanterr: `converting driver.Value type string ("3.5") to a int64: value loses precision`},
+		{policy: NumericLossless, s: float64(1.5), d: &f32, want: float32(1.5)},
+		{policy: NumericLossless, s: float64(0.1), d: &f32, wanterr: `converting driver.Value type float64 ("0.1") to a float32: value loses precision`},
+		{policy: NumericLossless, s: "0.1", d: &f64, want: 0.1},
+		{policy: NumericLossless, s: "16777217", d: &f32, wanterr: `converting driver.Value type string ("16777217") to a float32: value loses precision`},
+		{policy: NumericLossless, s: int64(1<<53 + 1), d: &f64, wanterr: `converting driver.Value type int64 ("9007199254740993") to a float64: value loses precision`},
+		{policy: NumericLossless, s: int64(1 << 53), d: &f64, want: float64(1 << 53)},
+	}
+	for _, tt := range tests {
+		sc := newScanner([]Option{WithNumericPolicy(tt.policy)})
+		err := sc.convertAssign(tt.d, tt.s)
+		name := fmt.Sprintf("policy %d: convertAssign(%T, %#v)", tt.policy, tt.d, tt.s)
+		errstr := ""
+		if err != nil {
+			errstr = err.Error()
+		}
+		if errstr != tt.wanterr {
+			t.Errorf("%s: got error %q, want %q", name, errstr, tt.wanterr)
+			continue
+		}
+		if tt.wanterr != "" {
+			continue
+		}
+		if got := reflect.ValueOf(tt.d).Elem().Interface(); got != tt.want {
+			t.Errorf("%s: got %v, want %v", name, got, tt.want)
+		}
+	}
+}
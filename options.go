@@ -0,0 +1,94 @@
+package scan
+
+import (
+	"time"
+)
+
+// Scanner holds the settings used to map columns and convert their values.
+// The zero value uses the package defaults.
+type Scanner struct {
+	location      *time.Location
+	numeric       NumericPolicy
+	boolWords     map[string]bool
+	mapper        func(string) string
+	ignoreCase    bool
+	qualifiers    bool
+	strictColumns bool
+	tagSources    []TagSource
+}
+
+// Option configures the Scanner used by Row and Rows.
+type Option func(*Scanner)
+
+// WithTimeLocation converts scanned time values into loc.
+// A nil loc keeps time values exactly as returned by the driver, which is the default.
+func WithTimeLocation(loc *time.Location) Option {
+	return func(sc *Scanner) {
+		sc.location = loc
+	}
+}
+
+// WithUTC converts scanned time values into UTC.
+func WithUTC() Option {
+	return WithTimeLocation(time.UTC)
+}
+
+// WithIgnoreCase matches column names to tags and field names regardless of
+// case, so the column ID maps to `db:"id"` and USER_NAME to UserName.
+func WithIgnoreCase() Option {
+	return func(sc *Scanner) {
+		sc.ignoreCase = true
+	}
+}
+
+// WithTableQualifiers matches table qualified columns such as users.id, as
+// returned by some drivers. Such a column maps, in order of precedence, to
+// the field its whole name maps to, to the field id of the struct field for
+// the table (User or Users), or to the field id.
+func WithTableQualifiers() Option {
+	return func(sc *Scanner) {
+		sc.qualifiers = true
+	}
+}
+
+// WithStrictColumns makes Row and Rows fail with ErrDuplicateColumn when
+// several columns would be scanned into the same field, as with the two id
+// columns of SELECT * FROM a JOIN b, instead of the last column winning.
+// Tag fields `db:"id#2"` to bind the second id column, or `db:"@3"` to bind
+// the third column whatever its name.
+func WithStrictColumns() Option {
+	return func(sc *Scanner) {
+		sc.strictColumns = true
+	}
+}
+
+// WithTagSources reads the column names and options of struct fields from
+// the first of sources that has a tag for the field, instead of the `db` tag.
+//
+//	scan.WithTagSources(scan.TagKey("db"), scan.GormTag, scan.TagKey("json"))
+func WithTagSources(sources ...TagSource) Option {
+	return func(sc *Scanner) {
+		sc.tagSources = sources
+	}
+}
+
+var defaultScanner = &Scanner{}
+
+func newScanner(opts []Option) *Scanner {
+	if len(opts) == 0 {
+		return defaultScanner
+	}
+	sc := &Scanner{}
+	for _, opt := range opts {
+		opt(sc)
+	}
+	return sc
+}
+
+// inLocation applies the time location policy to t.
+func (sc *Scanner) inLocation(t time.Time) time.Time {
+	if sc.location == nil {
+		return t
+	}
+	return t.In(sc.location)
+}
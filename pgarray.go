@@ -0,0 +1,219 @@
+package scan
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// pgArray is a parsed Postgres array literal such as {1,2,NULL} or {{a,b},{c,d}}.
+// Each element is a string, nil for NULL, or a nested pgArray.
+type pgArray []any
+
+// isPgArray reports whether s looks like a Postgres array literal, optionally
+// decorated with its dimensions as in [0:1]={1,2}. Other values starting
+// with "[", such as JSON arrays, are not array literals.
+func isPgArray(s string) bool {
+	if !strings.HasPrefix(s, "[") {
+		return strings.HasPrefix(s, "{")
+	}
+	for strings.HasPrefix(s, "[") {
+		dim, rest, ok := strings.Cut(s[1:], "]")
+		if !ok {
+			return false
+		}
+		lower, upper, ok := strings.Cut(dim, ":")
+		if !ok || !isInteger(lower) || !isInteger(upper) {
+			return false
+		}
+		s = rest
+	}
+	return strings.HasPrefix(s, "=")
+}
+
+// isInteger reports whether s is a decimal integer with an optional minus sign.
+func isInteger(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+func parsePgArray(s string) (pgArray, error) {
+	if strings.HasPrefix(s, "[") {
+		_, after, ok := strings.Cut(s, "=")
+		if !ok {
+			return nil, fmt.Errorf("malformed array literal: missing \"=\" after dimensions")
+		}
+		s = after
+	}
+	p := pgArrayParser{s: s}
+	arr, err := p.array()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return nil, p.errorf("unexpected %q after array", p.s[p.pos:])
+	}
+	return arr, nil
+}
+
+type pgArrayParser struct {
+	s   string
+	pos int
+}
+
+func (p *pgArrayParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("malformed array literal at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *pgArrayParser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *pgArrayParser) array() (pgArray, error) {
+	p.skipSpace()
+	if p.pos >= len(p.s) || p.s[p.pos] != '{' {
+		return nil, p.errorf("expected \"{\"")
+	}
+	p.pos++
+
+	arr := pgArray{}
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == '}' {
+		p.pos++
+		return arr, nil
+	}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			return nil, p.errorf("unexpected end of input")
+		}
+		switch p.s[p.pos] {
+		case '{':
+			sub, err := p.array()
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, sub)
+		case '"':
+			elem, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, elem)
+		default:
+			elem, null, err := p.unquoted()
+			if err != nil {
+				return nil, err
+			}
+			if null {
+				arr = append(arr, nil)
+			} else {
+				arr = append(arr, elem)
+			}
+		}
+
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			return nil, p.errorf("unexpected end of input")
+		}
+		switch p.s[p.pos] {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return arr, nil
+		default:
+			return nil, p.errorf("unexpected %q", p.s[p.pos])
+		}
+	}
+}
+
+// quoted reads a double quoted element, in which a backslash escapes the next character.
+func (p *pgArrayParser) quoted() (string, error) {
+	p.pos++ // opening quote
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.pos >= len(p.s) {
+				return "", p.errorf("unexpected end of input")
+			}
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated quoted element")
+}
+
+// unquoted reads an element up to the next delimiter. The word NULL, in any
+// case and without escapes, is reported as null.
+func (p *pgArrayParser) unquoted() (elem string, null bool, err error) {
+	var b strings.Builder
+	escaped := false
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if c == ',' || c == '}' || c == '{' || c == '"' {
+			break
+		}
+		p.pos++
+		if c == '\\' {
+			if p.pos >= len(p.s) {
+				return "", false, p.errorf("unexpected end of input")
+			}
+			c = p.s[p.pos]
+			p.pos++
+			escaped = true
+		}
+		b.WriteByte(c)
+	}
+	elem = strings.TrimSpace(b.String())
+	if elem == "" {
+		return "", false, p.errorf("empty element")
+	}
+	return elem, !escaped && strings.EqualFold(elem, "NULL"), nil
+}
+
+// assignPgArray converts every element of arr into dv, which is a slice or
+// an array of exactly len(arr) elements. Nested arrays go into nested slices
+// or arrays.
+func (sc *Scanner) assignPgArray(dv reflect.Value, arr pgArray) error {
+	var out reflect.Value
+	switch dv.Kind() {
+	case reflect.Slice:
+		out = reflect.MakeSlice(dv.Type(), len(arr), len(arr))
+	case reflect.Array:
+		if len(arr) != dv.Len() {
+			return fmt.Errorf("got %d elements, want %d", len(arr), dv.Len())
+		}
+		out = reflect.New(dv.Type()).Elem()
+	default:
+		return fmt.Errorf("%s is not a slice or array", dv.Type())
+	}
+
+	for i, elem := range arr {
+		ev := out.Index(i)
+		if sub, ok := elem.(pgArray); ok {
+			if ev.Kind() != reflect.Slice && ev.Kind() != reflect.Array {
+				return fmt.Errorf("element %d: array has more dimensions than %s", i, dv.Type())
+			}
+			if err := sc.assignPgArray(ev, sub); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+			continue
+		}
+		if err := sc.convertAssign(ev.Addr().Interface(), elem); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	dv.Set(out)
+	return nil
+}
@@ -0,0 +1,98 @@
+package scan
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParsePgArray(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    pgArray
+		wanterr string
+	}{
+		{in: "{}", want: pgArray{}},
+		{in: "{1,2,3}", want: pgArray{"1", "2", "3"}},
+		{in: "{a,NULL,null,\"NULL\"}", want: pgArray{"a", nil, nil, "NULL"}},
+		{in: `{"a b","c\"d","e\\f",""}`, want: pgArray{"a b", `c"d`, `e\f`, ""}},
+		{in: `{a\,b,\NULL}`, want: pgArray{"a,b", "NULL"}},
+		{in: "{ 1 , 2 }", want: pgArray{"1", "2"}},
+		{in: "{{1,2},{3,4}}", want: pgArray{pgArray{"1", "2"}, pgArray{"3", "4"}}},
+		{in: "[0:1]={1,2}", want: pgArray{"1", "2"}},
+		{in: "{1,2", wanterr: "malformed array literal at offset 4: unexpected end of input"},
+		{in: "{1,,2}", wanterr: "malformed array literal at offset 3: empty element"},
+		{in: `{"a}`, wanterr: "malformed array literal at offset 4: unterminated quoted element"},
+		{in: "{1}x", wanterr: `malformed array literal at offset 3: unexpected "x" after array`},
+		{in: "[0:1]{1,2}", wanterr: `malformed array literal: missing "=" after dimensions`},
+	}
+	for _, tt := range tests {
+		got, err := parsePgArray(tt.in)
+		errstr := ""
+		if err != nil {
+			errstr = err.Error()
+		}
+		if errstr != tt.wanterr {
+			t.Errorf("parsePgArray(%q): got error %q, want %q", tt.in, errstr, tt.wanterr)
+			continue
+		}
+		if tt.wanterr == "" && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePgArray(%q) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestConvertAssignPgArray(t *testing.T) {
+	tests := []struct {
+		s, d    any
+		want    any
+		wanterr string
+	}{
+		{s: "{a,b}", d: new([]string), want: []string{"a", "b"}},
+		{s: []byte("{1,2,NULL}"), d: new([]int64), want: []int64{1, 2, 0}},
+		{s: []byte("{1,2,NULL}"), d: new([]*int64), want: []*int64{ptrTo[int64](1), ptrTo[int64](2), nil}},
+		{s: "{{1,2},{3,4}}", d: new([][]int), want: [][]int{{1, 2}, {3, 4}}},
+		{s: "{t,f}", d: new([]bool), want: []bool{true, false}},
+		{s: "{}", d: new([]string), want: []string{}},
+		{s: "{1,x}", d: new([]int), wanterr: `converting driver.Value type string ("{1,x}") to a []int: element 1: converting driver.Value type string ("x") to a int: invalid syntax`},
+		{s: "{{1}}", d: new([]int), wanterr: `converting driver.Value type string ("{{1}}") to a []int: element 0: array has more dimensions than []int`},
+		{s: "{1.5,2,3}", d: new([3]float64), want: [3]float64{1.5, 2, 3}},
+		{s: "{{1,2},{3,4}}", d: new([2][2]int), want: [2][2]int{{1, 2}, {3, 4}}},
+		{s: "{{1,2},{3,4}}", d: new([][2]int), want: [][2]int{{1, 2}, {3, 4}}},
+		{s: "{1,2}", d: new([3]float64), wanterr: `converting driver.Value type string ("{1,2}") to a [3]float64: got 2 elements, want 3`},
+		{s: "1,2", d: new([]int), wanterr: `unsupported Scan, storing driver.Value type string into type *[]int`},
+		{s: "[1:2]={3,4}", d: new([]int), want: []int{3, 4}},
+		{s: "[1,2]", d: new([]int), wanterr: `unsupported Scan, storing driver.Value type string into type *[]int`},
+		{s: `["a","b"]`, d: new(jsonTags), want: jsonTags{"a", "b"}},
+		{s: []byte(`{"a":1}`), d: new(jsonTags), wanterr: `converting driver.Value type []uint8 ("{\"a\":1}") to a *scan.jsonTags: json: cannot unmarshal object into Go value of type []string`},
+	}
+	for _, tt := range tests {
+		err := convertAssign(tt.d, tt.s)
+		errstr := ""
+		if err != nil {
+			errstr = err.Error()
+		}
+		if errstr != tt.wanterr {
+			t.Errorf("convertAssign(%T, %v): got error %q, want %q", tt.d, tt.s, errstr, tt.wanterr)
+			continue
+		}
+		if tt.wanterr != "" {
+			continue
+		}
+		if got := reflect.ValueOf(tt.d).Elem().Interface(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("convertAssign(%T, %v): got %#v, want %#v", tt.d, tt.s, got, tt.want)
+		}
+	}
+}
+
+// jsonTags decodes itself from a JSON array, which must not be taken for a
+// Postgres array literal.
+type jsonTags []string
+
+func (t *jsonTags) UnmarshalText(b []byte) error {
+	return json.Unmarshal(b, (*[]string)(t))
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
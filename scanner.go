@@ -3,7 +3,11 @@ package scan
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"unicode"
 )
@@ -14,11 +18,64 @@ var (
 	// `select col1, col2 from mutable` to []string
 	ErrTooManyColumns = errors.New("too many columns returned for primitive slice")
 
+	// ErrDuplicateColumn indicates that several columns would be scanned into
+	// the same field, which WithStrictColumns rejects.
+	ErrDuplicateColumn = errors.New("duplicate column")
+
+	// ErrDuplicateKey indicates that two rows have the same key in Map.
+	ErrDuplicateKey = errors.New("duplicate key")
+
+	// ErrNullKey indicates that the key column of Map or MapSlice is NULL
+	// while the key type cannot represent NULL.
+	ErrNullKey = errors.New("NULL key")
+
 	// ScannerMapper transforms database field names into struct/map field names
 	// E.g. you can set function for convert snake_case into CamelCase
 	ScannerMapper = func(name string) string { return toTitleCase(name) }
 )
 
+// ScanError describes a column value that could not be converted into its destination.
+type ScanError struct {
+	// Column is the name of the column, empty when it is not known.
+	Column string
+	// Value is the value returned by the driver.
+	Value any
+	// Type is the type of the destination.
+	Type reflect.Type
+	Err  error
+}
+
+func (e *ScanError) Error() string {
+	msg := fmt.Sprintf("converting driver.Value type %T (%q) to a %s: %v", e.Value, asString(e.Value), e.Type, e.Err)
+	if e.Column != "" {
+		return fmt.Sprintf("column %q: %s", e.Column, msg)
+	}
+	return msg
+}
+
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+// columnScanner records the column name in ScanErrors returned by dest.
+type columnScanner struct {
+	dest   sql.Scanner
+	column string
+}
+
+func (c columnScanner) Scan(src any) error {
+	return withColumn(c.dest.Scan(src), c.column)
+}
+
+// withColumn records column in err when it is a ScanError without one.
+func withColumn(err error, column string) error {
+	var se *ScanError
+	if errors.As(err, &se) && se.Column == "" {
+		se.Column = column
+	}
+	return err
+}
+
 // toTitleCase converts a string to title case (first letter capitalized)
 func toTitleCase(s string) string {
 	if s == "" {
@@ -48,9 +105,10 @@ func capitalizeFirst(s string) string {
 
 // Row scans a single row and returns a value of type T.
 // It requires that you use db.Query and not db.QueryRow, because QueryRow does not return column names.
-func Row[T any](r *sql.Rows) (T, error) {
+// Options configure how columns are mapped and converted.
+func Row[T any](r *sql.Rows, opts ...Option) (T, error) {
 	var zero T
-	items, err := rowsGeneric[T](r)
+	items, err := rowsGeneric[T](r, newScanner(opts))
 	if err != nil {
 		return zero, err
 	}
@@ -61,86 +119,542 @@ func Row[T any](r *sql.Rows) (T, error) {
 }
 
 // Rows scans sql rows into a slice of T.
-func Rows[T any](r *sql.Rows) ([]T, error) {
-	return rowsGeneric[T](r)
+// Options configure how columns are mapped and converted.
+func Rows[T any](r *sql.Rows, opts ...Option) ([]T, error) {
+	return rowsGeneric[T](r, newScanner(opts))
 }
 
-func rowsGeneric[T any](r *sql.Rows) ([]T, error) {
-	cols, err := r.Columns()
+// RowInto scans the first row into dst. Fields that no column maps to keep
+// their values, so a row can be merged into an existing struct. Like Row, it
+// reads any remaining rows. The row is scanned into a shallow copy of dst that
+// is assigned only when it succeeds, so on error dst is left as it was, apart
+// from values reached through its pointers and maps. It returns sql.ErrNoRows,
+// leaving dst untouched, when there is no row.
+func RowInto[T any](r *sql.Rows, dst *T, opts ...Option) error {
+	item := *dst
+	found := false
+	err := scanRows(r, newScanner(opts), nil, func() *T {
+		if found {
+			return nil
+		}
+		found = true
+		return &item
+	})
+	if err != nil {
+		return err
+	}
+	for r.Next() {
+	}
+	if err := r.Err(); err != nil {
+		return err
+	}
+	if !found {
+		return sql.ErrNoRows
+	}
+	*dst = item
+	return nil
+}
+
+// AppendRows scans sql rows into values of T appended to dst, reusing its
+// capacity, and returns the extended slice. On error it returns dst with its
+// original length.
+func AppendRows[T any](dst []T, r *sql.Rows, opts ...Option) ([]T, error) {
+	return appendRows(dst, r, newScanner(opts))
+}
+
+func rowsGeneric[T any](r *sql.Rows, sc *Scanner) ([]T, error) {
+	out, err := appendRows[T](nil, r, sc)
 	if err != nil {
 		return nil, err
 	}
+	return out, nil
+}
+
+func appendRows[T any](dst []T, r *sql.Rows, sc *Scanner) ([]T, error) {
+	n := len(dst)
+	var zero T
+	err := scanRows(r, sc, nil, func() *T {
+		dst = append(dst, zero)
+		return &dst[len(dst)-1]
+	})
+	if err != nil {
+		return dst[:n], err
+	}
+	return dst, nil
+}
+
+// scanRows scans each row into the value next returns, until next returns nil.
+// When T is a pointer to a struct, at any depth, nil pointers are allocated
+// and the struct is scanned. A non-nil key captures the key column of each row.
+func scanRows[T any](r *sql.Rows, sc *Scanner, key *keyColumn, next func() *T) error {
+	itemType := reflect.TypeFor[T]()
+	baseType := rowType(itemType)
+	if baseType == itemType {
+		return scanInto(r, sc, itemType, key, func() any {
+			// a nil *T must become a nil any, not a non-nil any holding a nil pointer
+			if item := next(); item != nil {
+				return item
+			}
+			return nil
+		})
+	}
+	return scanInto(r, sc, baseType, key, func() any {
+		item := next()
+		if item == nil {
+			return nil
+		}
+		v := reflect.ValueOf(item).Elem()
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		return v.Addr().Interface()
+	})
+}
+
+// rowType returns the type the columns of a row are scanned into for rows of
+// typ: the type a pointer points to, at any depth, when columns are mapped to
+// it as a whole row, and typ itself otherwise. Pointers to values converted
+// from a single column, such as *time.Time, are left alone.
+func rowType(typ reflect.Type) reflect.Type {
+	base := typ
+	for base.Kind() == reflect.Pointer {
+		base = base.Elem()
+	}
+	if base == typ {
+		return typ
+	}
+	pt := reflect.PointerTo(base)
+	switch {
+	case pt.Implements(rowScannerType), implementsSetter(base):
+		return base
+	case base.Kind() != reflect.Struct,
+		pt.Implements(sqlScannerType), pt.Implements(textUnmarshalerType), pt.Implements(binaryUnmarshalerType):
+		return typ
+	}
+	return base
+}
+
+// scanInto scans each row into the pointer to itemType next returns, until
+// next returns nil.
+func scanInto(r *sql.Rows, sc *Scanner, itemType reflect.Type, key *keyColumn, next func() any) error {
+	cols, err := r.Columns()
+	if err != nil {
+		return err
+	}
+	if key != nil {
+		if key.index = slices.Index(cols, key.name); key.index < 0 {
+			return fmt.Errorf("scan: key column %q not found", key.name)
+		}
+	}
+	if len(cols) == 0 {
+		for r.Next() {
+		}
+		return r.Err()
+	}
 
-	var out []T
-	var t T
-	itemType := reflect.TypeOf(t)
-	if itemType == nil {
-		itemType = reflect.TypeOf((*any)(nil)).Elem()
+	if reflect.PointerTo(itemType).Implements(rowScannerType) {
+		return scanDirect(r, sc, cols, key, next)
+	}
+
+	isSetter := implementsSetter(itemType)
+	isPrimitive := !isSetter && itemType.Kind() != reflect.Struct
+
+	var mapping *structMapping
+	if !isPrimitive && !isSetter {
+		if prepare := lookupGenerated(itemType); prepare != nil && sc.defaultMapping() {
+			return scanGenerated(r, sc, cols, prepare, key, next)
+		}
+		if mapping, err = newStructMapping(sc, itemType, cols); err != nil {
+			return err
+		}
 	}
-	isPrimitive := itemType.Kind() != reflect.Struct
 
 	for r.Next() {
-		itemVal := reflect.New(itemType).Elem()
+		item := next()
+		if item == nil {
+			break
+		}
+		itemVal := reflect.ValueOf(item).Elem()
 
 		var pointers []any
-		if isPrimitive {
-			if len(cols) > 1 {
-				return nil, ErrTooManyColumns
+		switch {
+		case isSetter:
+			pointers = setterPointers(itemVal, cols)
+		case isPrimitive:
+			if pointers, err = primitivePointers(sc, item, cols, key); err != nil {
+				return err
 			}
-			pointers = []any{Nullable(itemVal.Addr().Interface())}
-		} else {
-			pointers = structPointers(itemVal, cols)
+		default:
+			pointers = structPointers(sc, itemVal, mapping, cols)
 		}
 
-		if len(pointers) == 0 {
-			continue
+		if err := key.scan(r, sc, pointers); err != nil {
+			return err
+		}
+	}
+	return r.Err()
+}
+
+// primitivePointers returns the r.Scan destinations of a row scanned into
+// the primitive item. The row has a single column, or two when one of them
+// is the key column.
+func primitivePointers(sc *Scanner, item any, cols []string, key *keyColumn) ([]any, error) {
+	value := 0
+	switch {
+	case key != nil && len(cols) == 2:
+		value = 1 - key.index
+	case len(cols) > 1:
+		return nil, ErrTooManyColumns
+	}
+	pointers := make([]any, len(cols))
+	for i := range pointers {
+		pointers[i] = new(any)
+	}
+	pointers[value] = columnScanner{dest: sc.nullable(item), column: cols[value]}
+	return pointers, nil
+}
+
+// field is a struct field, identified by its index path, together with its parsed tag.
+type field struct {
+	index []int
+	tag   fieldTag
+	match MatchKind
+}
+
+// structMapping records which struct field every column is scanned into.
+type structMapping struct {
+	// fields holds the field of each column, with a nil index when the column is not mapped.
+	fields []field
+	// extra is the index of the field collecting unmapped columns, nil when there is none.
+	extra []int
+}
+
+func newStructMapping(sc *Scanner, typ reflect.Type, cols []string) (*structMapping, error) {
+	m := &structMapping{fields: make([]field, len(cols))}
+	idx := sc.newFieldIndex(typ, &m.extra)
+
+	if m.extra != nil {
+		ft := typ.FieldByIndex(m.extra)
+		if ft.Type.Kind() != reflect.Map || ft.Type.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("scan: extra field %s must be a map with string keys, not %s", ft.Name, ft.Type)
 		}
+	}
 
-		if err := r.Scan(pointers...); err != nil {
+	occurrences := make(map[string]int, len(cols))
+	for i, colName := range cols {
+		key := idx.tagKey(colName)
+		occurrences[key]++
+		f, ok := idx.lookupPosition(i, colName, occurrences[key])
+		if !ok {
+			// A qualified column such as users.id matches in order: the whole
+			// name, the field id of the struct field for users, and then id.
+			f, ok = idx.lookup(colName)
+		}
+		if !ok && sc.qualifiers {
+			if table, name, found := cutQualifier(colName); found {
+				if f, ok = idx.lookupQualified(table, name); !ok {
+					f, ok = idx.lookup(name)
+				}
+				if ok {
+					f.match = MatchAlias
+				}
+			}
+		}
+		if m.extra != nil && slices.Equal(f.index, m.extra) {
+			f = field{}
+		}
+		m.fields[i] = f
+	}
+	if sc.strictColumns {
+		if err := m.checkDuplicates(typ, cols); err != nil {
 			return nil, err
 		}
+	}
+	return m, nil
+}
 
-		// append scanned item
-		out = append(out, itemVal.Interface().(T))
+// checkDuplicates returns an error when two columns are scanned into the same
+// field, or into the same key of the extra field.
+func (m *structMapping) checkDuplicates(typ reflect.Type, cols []string) error {
+	owners := make(map[string]int, len(cols))
+	for i, f := range m.fields {
+		var key, dest string
+		switch {
+		case f.index != nil:
+			key, dest = indexKey(f.index), fieldPath(typ, f.index)
+		case m.extra != nil:
+			key, dest = "extra "+cols[i], fieldPath(typ, m.extra)+"["+strconv.Quote(cols[i])+"]"
+		default:
+			continue
+		}
+		if j, ok := owners[key]; ok {
+			return fmt.Errorf("%w: columns %d (%q) and %d (%q) are both scanned into %s", ErrDuplicateColumn, j+1, cols[j], i+1, cols[i], dest)
+		}
+		owners[key] = i
 	}
-	return out, r.Err()
+	return nil
 }
 
-// Initialization the tags from struct.
-func initFieldTag(sliceItem reflect.Value, fieldTagMap *map[string]reflect.Value) {
-	typ := sliceItem.Type()
-	for i := 0; i < sliceItem.NumField(); i++ {
-		if typ.Field(i).Anonymous || typ.Field(i).Type.Kind() == reflect.Struct {
-			// found an embedded struct
-			sliceItemOfAnonymous := sliceItem.Field(i)
-			initFieldTag(sliceItemOfAnonymous, fieldTagMap)
+// indexKey formats the index path of a field as a map key.
+func indexKey(index []int) string {
+	return fmt.Sprint(index)
+}
+
+// fieldIndex finds the fields of a struct type that columns map to.
+type fieldIndex struct {
+	sc     *Scanner
+	typ    reflect.Type
+	tagged map[string]field
+	// mapped holds the fields by the key of their name, nil when columns are
+	// matched with FieldByName and ScannerMapper.
+	mapped map[string][]int
+}
+
+func (sc *Scanner) newFieldIndex(typ reflect.Type, extra *[]int) *fieldIndex {
+	idx := &fieldIndex{sc: sc, typ: typ, tagged: make(map[string]field)}
+	sc.initFieldTag(typ, nil, nil, idx.tagged, extra)
+	if sc.ignoreCase {
+		// sorted, so that of two tags differing in case the same one always wins
+		tagged := make(map[string]field, len(idx.tagged))
+		for _, name := range slices.Sorted(maps.Keys(idx.tagged)) {
+			tagged[strings.ToLower(name)] = idx.tagged[name]
 		}
-		tag, ok := typ.Field(i).Tag.Lookup("db")
-		if ok && tag != "" {
-			(*fieldTagMap)[tag] = sliceItem.Field(i)
+		idx.tagged = tagged
+	}
+	if sc.mapper != nil || sc.ignoreCase {
+		idx.mapped = mappedFields(typ, idx.fieldKey, func(index []int) bool {
+			return sc.ignoredPath(typ, index)
+		})
+	}
+	return idx
+}
+
+// fieldKey returns the key a field name is indexed by.
+func (idx *fieldIndex) fieldKey(name string) string {
+	if idx.sc.mapper != nil {
+		name = idx.sc.mapper(name)
+	}
+	if idx.sc.ignoreCase {
+		name = strings.ToLower(name)
+	}
+	return name
+}
+
+// columnKey returns the key of the field a column maps to.
+func (idx *fieldIndex) columnKey(col string) string {
+	if idx.sc.mapper != nil {
+		col = idx.sc.mapper(col)
+	} else {
+		col = ScannerMapper(col)
+	}
+	if idx.sc.ignoreCase {
+		col = strings.ToLower(col)
+	}
+	return col
+}
+
+// tagKey returns the tag name col matches.
+func (idx *fieldIndex) tagKey(col string) string {
+	if idx.sc.ignoreCase {
+		return strings.ToLower(col)
+	}
+	return col
+}
+
+// lookupPosition returns the field tagged with the ordinal of the column at
+// index i, as in `db:"@3"`, or with the occurrence n of its name, as in
+// `db:"id#2"`.
+func (idx *fieldIndex) lookupPosition(i int, col string, n int) (field, bool) {
+	if f, ok := idx.tagged["@"+strconv.Itoa(i+1)]; ok {
+		return f, true
+	}
+	f, ok := idx.tagged[idx.tagKey(col)+"#"+strconv.Itoa(n)]
+	return f, ok
+}
+
+// lookup returns the field col maps to, by tag first and then by name.
+func (idx *fieldIndex) lookup(col string) (field, bool) {
+	if f, ok := idx.tagged[idx.tagKey(col)]; ok {
+		return f, true
+	}
+	if idx.mapped != nil {
+		if index, ok := idx.mapped[idx.columnKey(col)]; ok {
+			return field{index: index, tag: idx.sc.structTag(idx.typ.FieldByIndex(index)), match: MatchMapper}, true
 		}
+		return field{}, false
 	}
+	if sf, ok := idx.typ.FieldByName(ScannerMapper(col)); ok && !idx.sc.ignoredPath(idx.typ, sf.Index) {
+		return field{index: sf.Index, tag: idx.sc.structTag(sf), match: MatchMapper}, true
+	}
+	return field{}, false
 }
 
-func structPointers(sliceItem reflect.Value, cols []string) []any {
-	pointers := make([]any, 0, len(cols))
-	fieldTag := make(map[string]reflect.Value, len(cols))
-	initFieldTag(sliceItem, &fieldTag)
-
-	for _, colName := range cols {
-		var fieldVal reflect.Value
-		if v, ok := fieldTag[colName]; ok {
-			fieldVal = v
-		} else {
-			fieldVal = sliceItem.FieldByName(ScannerMapper(colName))
-		}
-		if !fieldVal.IsValid() || !fieldVal.CanSet() {
-			var nothing any
-			pointers = append(pointers, &nothing)
+// lookupQualified returns the field name maps to inside the struct field
+// for table, which is looked up like a column and also in singular form,
+// so users.id maps to the field User.ID.
+func (idx *fieldIndex) lookupQualified(table, name string) (field, bool) {
+	names := []string{table}
+	if n := len(table); n > 1 && (table[n-1] == 's' || table[n-1] == 'S') {
+		names = append(names, table[:n-1])
+	}
+	for _, q := range names {
+		outer, ok := idx.lookup(q)
+		if !ok {
+			continue
+		}
+		ft := idx.typ.FieldByIndex(outer.index).Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() != reflect.Struct {
+			continue
+		}
+		var extra []int
+		if f, ok := idx.sc.newFieldIndex(ft, &extra).lookup(name); ok {
+			f.index = append(slices.Clone(outer.index), f.index...)
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// cutQualifier splits a column such as users.id or db.users.id into the
+// table and the column name.
+func cutQualifier(col string) (table, name string, found bool) {
+	i := strings.LastIndexByte(col, '.')
+	if i < 0 {
+		return "", col, false
+	}
+	table = col[:i]
+	if j := strings.LastIndexByte(table, '.'); j >= 0 {
+		table = table[j+1:]
+	}
+	return table, col[i+1:], true
+}
+
+// Initialization the tags from struct.
+func (sc *Scanner) initFieldTag(typ reflect.Type, parents []reflect.Type, index []int, fieldTagMap map[string]field, extra *[]int) {
+	parents = append(parents, typ)
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		fieldIndex := append(slices.Clone(index), i)
+		ft, ok := sc.lookupTag(sf)
+		if ft.ignored {
+			continue
+		}
+		if nested, ok := nestedStruct(sf, parents); ok {
+			// found an embedded or nested struct
+			sc.initFieldTag(nested, parents, fieldIndex, fieldTagMap, extra)
+		}
+		if !ok {
 			continue
 		}
+		if ft.extra {
+			*extra = fieldIndex
+			continue
+		}
+		if ft.name != "" {
+			fieldTagMap[ft.name] = field{index: fieldIndex, tag: ft, match: MatchTag}
+		}
+	}
+}
 
-		pointers = append(pointers, Nullable(fieldVal.Addr().Interface()))
+// nestedStruct returns the struct type holding the tagged fields sf brings
+// in: its own type when it is a struct, or the element type of an embedded
+// struct pointer, which fieldByIndex allocates. Embedded pointers to one of
+// parents, the structs being traversed, are skipped to stop the recursion.
+func nestedStruct(sf reflect.StructField, parents []reflect.Type) (reflect.Type, bool) {
+	t := sf.Type
+	if sf.Anonymous && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t, t.Kind() == reflect.Struct && !slices.Contains(parents, t)
+}
+
+func structPointers(sc *Scanner, sliceItem reflect.Value, mapping *structMapping, cols []string) []any {
+	pointers := make([]any, 0, len(cols))
+	for i := range mapping.fields {
+		pointers = append(pointers, columnPointer(sc, sliceItem, mapping, i, cols))
 	}
 	return pointers
 }
+
+// columnPointer returns the r.Scan destination of column i in sliceItem.
+func columnPointer(sc *Scanner, sliceItem reflect.Value, mapping *structMapping, i int, cols []string) any {
+	f := mapping.fields[i]
+	var fieldVal reflect.Value
+	if f.index != nil {
+		fieldVal = fieldByIndex(sliceItem, f.index)
+	}
+	if !fieldVal.IsValid() || !fieldVal.CanSet() {
+		var extras reflect.Value
+		if mapping.extra != nil {
+			extras = fieldByIndex(sliceItem, mapping.extra)
+		}
+		if extras.IsValid() {
+			if extras.IsNil() {
+				extras.Set(reflect.MakeMap(extras.Type()))
+			}
+			return columnScanner{dest: extraScanner{extras: extras, key: cols[i], scanner: sc}, column: cols[i]}
+		}
+		var nothing any
+		return &nothing
+	}
+	return columnScanner{dest: sc.fieldDest(fieldVal, f.tag), column: cols[i]}
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but allocates nil embedded
+// struct pointers on the way. It returns the zero Value when such a pointer
+// cannot be set.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// fieldDest returns the r.Scan destination for the field value v, applying its tag options.
+func (sc *Scanner) fieldDest(v reflect.Value, tag fieldTag) sql.Scanner {
+	var dest sql.Scanner
+	switch {
+	case tag.unit != 0:
+		dest = unixTime{dest: v.Addr().Interface(), unit: tag.unit, scanner: sc}
+	case tag.split != "":
+		dest = splitScanner{dest: v, sep: tag.split, scanner: sc}
+	default:
+		dest = sc.nullable(v.Addr().Interface())
+	}
+	if tag.codec != "" {
+		dest = newCodecScanner(dest, v, tag.codec)
+	}
+	return dest
+}
+
+// extraScanner stores a column that has no field of its own in the extras map
+// under the column name, converted to the map's element type.
+type extraScanner struct {
+	extras  reflect.Value
+	key     string
+	scanner *Scanner
+}
+
+func (e extraScanner) Scan(src any) error {
+	v := reflect.New(e.extras.Type().Elem())
+	if err := e.scanner.convertAssign(v.Interface(), src); err != nil {
+		return err
+	}
+	e.extras.SetMapIndex(reflect.ValueOf(e.key).Convert(e.extras.Type().Key()), v.Elem())
+	return nil
+}
@@ -1,13 +1,21 @@
 package scan_test
 
 import (
+	"bytes"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/netip"
 	"sync"
 	"testing"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/goapt/scan"
 	"github.com/goapt/scan/internal/assert"
+	"github.com/goapt/scan/internal/fakedb"
 	"github.com/goapt/scan/internal/require"
 )
 
@@ -243,3 +251,658 @@ func TestRowClosesEarly(t *testing.T) {
 	err := rows.Close()
 	assert.NoError(t, err)
 }
+
+func TestRowsConvertsTimeLocation(t *testing.T) {
+	rows := q(t, "SELECT CAST('2024-01-02 03:04:05' AS DATETIME) AS created, CAST('2024-01-02 03:04:05' AS DATETIME) AS updated, CAST('2024-01-02 03:04:05' AS DATETIME) AS deleted")
+	defer rows.Close()
+	type Item struct {
+		Created time.Time
+		Updated *time.Time
+		Deleted sql.Null[time.Time]
+	}
+	item, err := scan.Row[Item](rows, scan.WithUTC())
+	require.NoError(t, err)
+	require.NotNil(t, item.Updated)
+	assert.Equal(t, time.UTC, item.Created.Location())
+	assert.Equal(t, time.UTC, item.Updated.Location())
+	assert.Equal(t, time.UTC, item.Deleted.V.Location())
+	assert.Equal(t, "2024-01-01 19:04:05", item.Created.Format(time.DateTime))
+	assert.Equal(t, true, item.Created.Equal(*item.Updated))
+	assert.Equal(t, true, item.Created.Equal(item.Deleted.V))
+}
+
+func TestRowsConvertsUnixTimestamps(t *testing.T) {
+	rows := q(t, "SELECT 1700000000 AS created_at, 1700000000123 AS updated_at, '1700000000.5' AS seen_at, 0 AS deleted_at, NULL AS archived_at")
+	defer rows.Close()
+	type Item struct {
+		CreatedAt  time.Time           `db:"created_at,unix"`
+		UpdatedAt  time.Time           `db:"updated_at,unixmilli"`
+		SeenAt     *time.Time          `db:"seen_at,unix"`
+		DeletedAt  *time.Time          `db:"deleted_at,unix"`
+		ArchivedAt sql.Null[time.Time] `db:"archived_at,unixnano"`
+	}
+	item, err := scan.Row[Item](rows)
+	require.NoError(t, err)
+	assert.Equal(t, true, item.CreatedAt.Equal(time.Unix(1700000000, 0)))
+	assert.Equal(t, true, item.UpdatedAt.Equal(time.UnixMilli(1700000000123)))
+	require.NotNil(t, item.SeenAt)
+	assert.Equal(t, true, item.SeenAt.Equal(time.Unix(1700000000, 5e8)))
+	assert.Nil(t, item.DeletedAt)
+	assert.Equal(t, false, item.ArchivedAt.Valid)
+}
+
+func TestRowsConvertsStdTypes(t *testing.T) {
+	rows := q(t, "SELECT '1h30m' AS timeout, '10.0.0.1' AS ip, '10.0.0.0/8' AS network, UNHEX('6BA7B8109DAD11D180B400C04FD430C8') AS id")
+	defer rows.Close()
+	type Item struct {
+		Timeout time.Duration
+		IP      netip.Addr `db:"ip"`
+		Network netip.Prefix
+		ID      [16]byte `db:"id"`
+	}
+	item, err := scan.Row[Item](rows)
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, item.Timeout)
+	assert.Equal(t, netip.MustParseAddr("10.0.0.1"), item.IP)
+	assert.Equal(t, netip.MustParsePrefix("10.0.0.0/8"), item.Network)
+	assert.Equal(t, [16]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}, item.ID)
+}
+
+func TestRowsScansDecimals(t *testing.T) {
+	const amount = "12345678901234567890.1234567890"
+	type Item struct {
+		Amount *big.Rat
+		Total  big.Float
+		Count  big.Int
+	}
+	rows := q(t, "SELECT CAST(? AS DECIMAL(38,10)) AS amount, CAST(? AS DECIMAL(38,10)) AS total, CAST(? AS DECIMAL(38,0)) AS count", amount, amount, "123456789012345678901234567890")
+	defer rows.Close()
+	item, err := scan.Row[Item](rows)
+	require.NoError(t, err)
+	require.NotNil(t, item.Amount)
+	assert.Equal(t, amount, item.Amount.FloatString(10))
+	assert.Equal(t, amount, item.Total.Text('f', 10))
+	assert.Equal(t, "123456789012345678901234567890", item.Count.String())
+
+	rows = q(t, "SELECT CAST(? AS DECIMAL(38,10)) AS amount", amount)
+	defer rows.Close()
+	_, err = scan.Row[float64](rows, scan.WithNumericPolicy(scan.NumericLossless))
+	assert.Error(t, err)
+}
+
+func TestRowsNumericPolicy(t *testing.T) {
+	rows := q(t, "SELECT 6 / 2 AS ratio")
+	defer rows.Close()
+	ratio, err := scan.Row[int](rows, scan.WithNumericPolicy(scan.NumericLenient))
+	require.NoError(t, err)
+	assert.Equal(t, 3, ratio)
+
+	rows = q(t, "SELECT 6 / 2 AS ratio")
+	defer rows.Close()
+	_, err = scan.Row[int](rows)
+	assert.Error(t, err)
+}
+
+func TestRowsConvertsLenientBools(t *testing.T) {
+	rows := q(t, "SELECT 'Y' AS active, 'no' AS deleted")
+	defer rows.Close()
+	type Item struct {
+		Active  bool
+		Deleted bool
+	}
+	item, err := scan.Row[Item](rows, scan.WithLenientBools())
+	require.NoError(t, err)
+	assert.Equal(t, true, item.Active)
+	assert.Equal(t, false, item.Deleted)
+}
+
+type userStatus int
+
+const (
+	userActive userStatus = iota + 1
+	userSuspended
+)
+
+func TestRowsConvertsEnums(t *testing.T) {
+	scan.RegisterEnum(map[string]userStatus{"active": userActive, "suspended": userSuspended})
+	type Item struct {
+		Status userStatus
+	}
+
+	rows := q(t, "SELECT 'suspended' AS status")
+	defer rows.Close()
+	item, err := scan.Row[Item](rows)
+	require.NoError(t, err)
+	assert.Equal(t, userSuspended, item.Status)
+
+	rows = q(t, "SELECT 'deleted' AS status")
+	defer rows.Close()
+	_, err = scan.Row[Item](rows)
+	var se *scan.ScanError
+	if !errors.As(err, &se) {
+		t.Fatalf("expected *scan.ScanError, got: %v", err)
+	}
+	assert.Equal(t, "status", se.Column)
+}
+
+func TestRowsSplitsDelimitedColumns(t *testing.T) {
+	rows := q(t, "SELECT 'a,b,c' AS tags, '1;2;3' AS ids, '' AS blank, NULL AS missing")
+	defer rows.Close()
+	type Item struct {
+		Tags    []string `db:"tags,split=,"`
+		IDs     []int64  `db:"ids,split=;"`
+		Blank   []string `db:"blank,split=,"`
+		Missing []string `db:"missing,split=,"`
+	}
+	item, err := scan.Row[Item](rows)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, item.Tags)
+	assert.Equal(t, []int64{1, 2, 3}, item.IDs)
+	assert.Equal(t, []string{}, item.Blank)
+	assert.Equal(t, []string(nil), item.Missing)
+}
+
+func TestRowsDecodesArrayLiterals(t *testing.T) {
+	rows := q(t, `SELECT '{1,2,NULL}' AS ids, '{"a b",c}' AS names, '{{1,2},{3,4}}' AS grid`)
+	defer rows.Close()
+	type Item struct {
+		IDs   []*int `db:"ids"`
+		Names []string
+		Grid  [][]float64
+	}
+	item, err := scan.Row[Item](rows)
+	require.NoError(t, err)
+	require.Len(t, item.IDs, 3)
+	assert.Equal(t, 1, *item.IDs[0])
+	assert.Equal(t, 2, *item.IDs[1])
+	assert.Nil(t, item.IDs[2])
+	assert.Equal(t, []string{"a b", "c"}, item.Names)
+	assert.Equal(t, [][]float64{{1, 2}, {3, 4}}, item.Grid)
+}
+
+func TestRowsScansFixedSizeArrays(t *testing.T) {
+	rows := q(t, "SELECT UNHEX('0A000001') AS addr, '1.5,2,3' AS point, '{1,2}' AS pair")
+	defer rows.Close()
+	type Item struct {
+		Addr  [4]byte
+		Point [3]float64 `db:"point,split=,"`
+		Pair  [2]int
+	}
+	item, err := scan.Row[Item](rows)
+	require.NoError(t, err)
+	assert.Equal(t, [4]byte{10, 0, 0, 1}, item.Addr)
+	assert.Equal(t, [3]float64{1.5, 2, 3}, item.Point)
+	assert.Equal(t, [2]int{1, 2}, item.Pair)
+
+	rows = q(t, "SELECT '1,2' AS point")
+	defer rows.Close()
+	_, err = scan.Row[Item](rows)
+	assert.Error(t, err)
+}
+
+func TestRowsDecodesCodecFields(t *testing.T) {
+	scan.RegisterCodec("upper", func(b []byte) ([]byte, error) {
+		return bytes.ToUpper(b), nil
+	})
+	rows := q(t, "SELECT 'aGVsbG8=' AS greeting, '6a6f6e6573' AS name, NULL AS note")
+	defer rows.Close()
+	type Item struct {
+		Greeting string  `db:"greeting,codec=base64"`
+		Name     string  `db:"name,codec=hex|upper"`
+		Note     *string `db:"note,codec=base64"`
+	}
+	item, err := scan.Row[Item](rows)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", item.Greeting)
+	assert.Equal(t, "JONES", item.Name)
+	assert.Nil(t, item.Note)
+
+	rows = q(t, "SELECT 'not base64' AS greeting")
+	defer rows.Close()
+	_, err = scan.Row[Item](rows)
+	var se *scan.ScanError
+	if !errors.As(err, &se) {
+		t.Fatalf("expected *scan.ScanError, got: %v", err)
+	}
+	assert.Equal(t, "greeting", se.Column)
+}
+
+func TestRowsEmbeddedPointerTags(t *testing.T) {
+	type Base struct {
+		ID int64 `db:"id"`
+	}
+	type Order struct {
+		*Base
+		Extras map[string]string `db:",extra"`
+	}
+	rows := q(t, "SELECT 7 AS id, 'v' AS note")
+	defer rows.Close()
+	order, err := scan.Row[Order](rows)
+	require.NoError(t, err)
+	require.NotNil(t, order.Base)
+	assert.Equal(t, int64(7), order.ID)
+	assert.Equal(t, map[string]string{"note": "v"}, order.Extras)
+
+	// an embedded pointer to the struct itself does not recurse forever
+	type Node struct {
+		*Node
+		Name string `db:"name"`
+	}
+	rows = q(t, "SELECT 'leaf' AS name")
+	defer rows.Close()
+	node, err := scan.Row[Node](rows)
+	require.NoError(t, err)
+	assert.Equal(t, "leaf", node.Name)
+}
+
+func TestRowsCollectsExtraColumns(t *testing.T) {
+	rows := q(t, "SELECT 1 AS id, 'brett' AS name, 42 AS score, NULL AS rank_no")
+	defer rows.Close()
+	type Item struct {
+		ID     int               `db:"id"`
+		Extras map[string]string `db:",extra"`
+	}
+	item, err := scan.Row[Item](rows)
+	require.NoError(t, err)
+	assert.Equal(t, 1, item.ID)
+	assert.Equal(t, map[string]string{"name": "brett", "score": "42", "rank_no": ""}, item.Extras)
+
+	rows = q(t, "SELECT 1 AS id, 'brett' AS name")
+	defer rows.Close()
+	type AnyItem struct {
+		ID     int            `db:"id"`
+		Extras map[string]any `db:",extra"`
+	}
+	anyItem, err := scan.Row[AnyItem](rows)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": []byte("brett")}, anyItem.Extras)
+
+	rows = q(t, "SELECT 1 AS id")
+	defer rows.Close()
+	type BadItem struct {
+		Extras []string `db:",extra"`
+	}
+	_, err = scan.Row[BadItem](rows)
+	assert.Error(t, err)
+}
+
+type attributeBag map[string]string
+
+func (b *attributeBag) ScanColumn(name string, src any) error {
+	if *b == nil {
+		*b = attributeBag{}
+	}
+	switch v := src.(type) {
+	case []byte:
+		(*b)[name] = string(v)
+	case nil:
+		(*b)[name] = "NULL"
+	default:
+		(*b)[name] = fmt.Sprint(v)
+	}
+	return nil
+}
+
+type wrappedUser struct {
+	id   int64
+	name string
+}
+
+func (u *wrappedUser) ScanDest(cols []string) ([]any, error) {
+	dests := make([]any, len(cols))
+	for i, col := range cols {
+		switch col {
+		case "id":
+			dests[i] = &u.id
+		case "name":
+			dests[i] = scan.Nullable(&u.name)
+		default:
+			dests[i] = new(any)
+		}
+	}
+	return dests, nil
+}
+
+type shortUser struct{}
+
+func (*shortUser) ScanDest([]string) ([]any, error) {
+	return nil, nil
+}
+
+func TestRowsUsesColumnSetters(t *testing.T) {
+	rows := q(t, "SELECT 'brett' AS name, 40 AS age, NULL AS email UNION ALL SELECT 'fred', 50, 'f@example.com'")
+	defer rows.Close()
+	bags, err := scan.Rows[attributeBag](rows)
+	require.NoError(t, err)
+	assert.Equal(t, []attributeBag{
+		{"name": "brett", "age": "40", "email": "NULL"},
+		{"name": "fred", "age": "50", "email": "f@example.com"},
+	}, bags)
+
+	rows = q(t, "SELECT 7 AS id, NULL AS name, 'x' AS other")
+	defer rows.Close()
+	user, err := scan.Row[wrappedUser](rows)
+	require.NoError(t, err)
+	assert.Equal(t, wrappedUser{id: 7}, user)
+
+	rows = q(t, "SELECT 7 AS id")
+	defer rows.Close()
+	_, err = scan.Row[shortUser](rows)
+	assert.Equal(t, "scan: *scan_test.shortUser.ScanDest returned 0 destinations for 1 columns", err.Error())
+
+	// keyed results check the destinations before looking up the key column
+	rows = q(t, "SELECT 7 AS id, 'x' AS name")
+	defer rows.Close()
+	_, err = scan.Map[int64, shortUser](rows, "id")
+	assert.Equal(t, "scan: *scan_test.shortUser.ScanDest returned 0 destinations for 2 columns", err.Error())
+
+	rows = q(t, "SELECT 7 AS id, 'x' AS name")
+	defer rows.Close()
+	_, err = scan.MapSlice[int64, shortUser](rows, "id")
+	assert.Error(t, err)
+}
+
+type benchUser struct {
+	ID    int64  `db:"id"`
+	Name  string `db:"name"`
+	Email string `db:"email"`
+}
+
+type fastUser benchUser
+
+func (u *fastUser) ScanDest(cols []string) ([]any, error) {
+	dests := make([]any, len(cols))
+	for i, col := range cols {
+		switch col {
+		case "id":
+			dests[i] = &u.ID
+		case "name":
+			dests[i] = &u.Name
+		case "email":
+			dests[i] = &u.Email
+		default:
+			return nil, fmt.Errorf("unexpected column %q", col)
+		}
+	}
+	return dests, nil
+}
+
+const benchQuery = "SELECT 1 AS id, 'brett' AS name, 'brett@example.com' AS email UNION ALL SELECT 2, 'fred', 'fred@example.com'"
+
+func TestRowsUsesRowScanner(t *testing.T) {
+	rows := q(t, benchQuery)
+	defer rows.Close()
+	users, err := scan.Rows[fastUser](rows)
+	require.NoError(t, err)
+	assert.Equal(t, []fastUser{
+		{ID: 1, Name: "brett", Email: "brett@example.com"},
+		{ID: 2, Name: "fred", Email: "fred@example.com"},
+	}, users)
+
+	rows = q(t, "SELECT 1 AS id, 2 AS other")
+	defer rows.Close()
+	_, err = scan.Row[fastUser](rows)
+	assert.Error(t, err)
+
+	// destinations wrapped with Nullable use the options of the call
+	rows = q(t, "SELECT 7 AS id, 'Y' AS name")
+	defer rows.Close()
+	flagged, err := scan.Row[flaggedUser](rows, scan.WithLenientBools())
+	require.NoError(t, err)
+	assert.Equal(t, flaggedUser{id: 7, flag: true}, flagged)
+}
+
+type flaggedUser struct {
+	id   int64
+	flag bool
+}
+
+func (u *flaggedUser) ScanDest([]string) ([]any, error) {
+	return []any{&u.id, scan.Nullable(&u.flag)}, nil
+}
+
+// benchDB returns 100 rows shaped like the result of benchQuery from memory.
+func benchDB() *sql.DB {
+	values := make([][]driver.Value, 100)
+	for i := range values {
+		values[i] = []driver.Value{int64(i), []byte("brett"), []byte("brett@example.com")}
+	}
+	return fakedb.Open([]string{"id", "name", "email"}, values)
+}
+
+func BenchmarkRowsReflect(b *testing.B) {
+	db := benchDB()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rows := fakedb.Query(b, db)
+		if _, err := scan.Rows[benchUser](rows); err != nil {
+			b.Fatal(err)
+		}
+		rows.Close()
+	}
+}
+
+func BenchmarkRowsScanDest(b *testing.B) {
+	db := benchDB()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rows := fakedb.Query(b, db)
+		if _, err := scan.Rows[fastUser](rows); err != nil {
+			b.Fatal(err)
+		}
+		rows.Close()
+	}
+}
+
+func TestRowsWithMapper(t *testing.T) {
+	type User struct {
+		UserID    int64
+		HomeURL   string
+		FirstName string
+	}
+	rows := q(t, "SELECT 7 AS user_id, 'https://example.com' AS home_url, 'Ann' AS first_name")
+	defer rows.Close()
+	item, err := scan.Row[User](rows, scan.WithMapper(scan.SnakeToGo))
+	require.NoError(t, err)
+	assert.Equal(t, User{UserID: 7, HomeURL: "https://example.com", FirstName: "Ann"}, item)
+
+	rows = q(t, "SELECT 7 AS USERID, 'Ann' AS firstname")
+	defer rows.Close()
+	item, err = scan.Row[User](rows, scan.WithMapper(scan.Lower))
+	require.NoError(t, err)
+	assert.Equal(t, User{UserID: 7, FirstName: "Ann"}, item)
+}
+
+func TestRowsWithTableQualifiersAndIgnoreCase(t *testing.T) {
+	type User struct {
+		ID   int64 `db:"id"`
+		Name string
+	}
+	type Order struct {
+		ID   int64 `db:"order_id"`
+		User User
+	}
+	rows := q(t, "SELECT 3 AS `orders.ORDER_ID`, 7 AS `USERS.ID`, 'Ann' AS `users.name`")
+	defer rows.Close()
+	item, err := scan.Row[Order](rows, scan.WithTableQualifiers(), scan.WithIgnoreCase())
+	require.NoError(t, err)
+	assert.Equal(t, Order{ID: 3, User: User{ID: 7, Name: "Ann"}}, item)
+}
+
+func TestRowsBindsDuplicateColumns(t *testing.T) {
+	type Join struct {
+		UserID  int64  `db:"id"`
+		OrderID int64  `db:"id#2"`
+		Status  string `db:"@3"`
+	}
+	rows := q(t, "SELECT 1 AS id, 2 AS id, 'paid' AS status")
+	defer rows.Close()
+	item, err := scan.Row[Join](rows, scan.WithStrictColumns())
+	require.NoError(t, err)
+	assert.Equal(t, Join{UserID: 1, OrderID: 2, Status: "paid"}, item)
+
+	type User struct {
+		ID int64 `db:"id"`
+	}
+	rows = q(t, "SELECT 1 AS id, 2 AS id")
+	defer rows.Close()
+	_, err = scan.Row[User](rows, scan.WithStrictColumns())
+	assert.Equal(t, true, errors.Is(err, scan.ErrDuplicateColumn))
+}
+
+func TestRowsWithTagSources(t *testing.T) {
+	type User struct {
+		ID    int64  `gorm:"primaryKey;column:user_id"`
+		Email string `json:"email_address"`
+		Name  string `db:"full_name" json:"name"`
+	}
+	rows := q(t, "SELECT 7 AS user_id, 'a@example.com' AS email_address, 'Ann' AS full_name")
+	defer rows.Close()
+	item, err := scan.Row[User](rows, scan.WithTagSources(scan.TagKey("db"), scan.GormTag, scan.TagKey("json")))
+	require.NoError(t, err)
+	assert.Equal(t, User{ID: 7, Email: "a@example.com", Name: "Ann"}, item)
+}
+
+func TestRowInto(t *testing.T) {
+	type User struct {
+		ID    int64 `db:"id"`
+		Name  string
+		Email string
+	}
+	dst := User{ID: 1, Name: "old", Email: "a@example.com"}
+	rows := q(t, "SELECT 2 AS id, 'Ann' AS name UNION ALL SELECT 3, 'Bob'")
+	defer rows.Close()
+	require.NoError(t, scan.RowInto(rows, &dst))
+	assert.Equal(t, User{ID: 2, Name: "Ann", Email: "a@example.com"}, dst)
+	// the remaining row has been read
+	assert.Equal(t, false, rows.Next())
+
+	rows = q(t, "SELECT 'Zed' AS name, 'x' AS id")
+	defer rows.Close()
+	err := scan.RowInto(rows, &dst)
+	require.Error(t, err)
+	assert.Equal(t, User{ID: 2, Name: "Ann", Email: "a@example.com"}, dst)
+
+	rows = q(t, "SELECT 2 AS id FROM (SELECT 1) AS t WHERE 1 = 0")
+	defer rows.Close()
+	err = scan.RowInto(rows, &dst)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Equal(t, User{ID: 2, Name: "Ann", Email: "a@example.com"}, dst)
+}
+
+func TestAppendRows(t *testing.T) {
+	dst := make([]int64, 1, 8)
+	dst[0] = 1
+	rows := q(t, "SELECT 2 AS n UNION ALL SELECT 3")
+	defer rows.Close()
+	out, err := scan.AppendRows(dst, rows)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, out)
+	assert.Equal(t, &dst[:1][0], &out[0])
+
+	rows = q(t, "SELECT 'x' AS n")
+	defer rows.Close()
+	out, err = scan.AppendRows(out, rows)
+	require.Error(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, out)
+}
+
+func TestRowsOfPointers(t *testing.T) {
+	type User struct {
+		ID   int64 `db:"id"`
+		Name string
+	}
+	rows := q(t, "SELECT 1 AS id, 'Ann' AS name UNION ALL SELECT 2, 'Bob'")
+	defer rows.Close()
+	users, err := scan.Rows[*User](rows)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, User{ID: 1, Name: "Ann"}, *users[0])
+	assert.Equal(t, User{ID: 2, Name: "Bob"}, *users[1])
+
+	rows = q(t, "SELECT 3 AS id, 'Cid' AS name")
+	defer rows.Close()
+	user, err := scan.Row[**User](rows)
+	require.NoError(t, err)
+	assert.Equal(t, User{ID: 3, Name: "Cid"}, **user)
+
+	var dst *User
+	rows = q(t, "SELECT 4 AS id")
+	defer rows.Close()
+	require.NoError(t, scan.RowInto(rows, &dst))
+	assert.Equal(t, User{ID: 4}, *dst)
+
+	rows = q(t, "SELECT CAST(NULL AS DATETIME) AS t UNION ALL SELECT CAST('2024-01-02 03:04:05' AS DATETIME)")
+	defer rows.Close()
+	times, err := scan.Rows[*time.Time](rows)
+	require.NoError(t, err)
+	require.Len(t, times, 2)
+	assert.Nil(t, times[0])
+	require.NotNil(t, times[1])
+}
+
+func TestMap(t *testing.T) {
+	type User struct {
+		ID   int64 `db:"id"`
+		Name string
+	}
+	rows := q(t, "SELECT '1' AS id, 'Ann' AS name UNION ALL SELECT '2', 'Bob'")
+	defer rows.Close()
+	users, err := scan.Map[int64, User](rows, "id")
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]User{1: {ID: 1, Name: "Ann"}, 2: {ID: 2, Name: "Bob"}}, users)
+
+	rows = q(t, "SELECT 1 AS id, 'Ann' AS name UNION ALL SELECT 2, 'Bob'")
+	defer rows.Close()
+	names, err := scan.Map[string, string](rows, "id")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"1": "Ann", "2": "Bob"}, names)
+
+	rows = q(t, "SELECT 1 AS id, 'Ann' AS name UNION ALL SELECT 1, 'Bob'")
+	defer rows.Close()
+	_, err = scan.Map[int64, *User](rows, "id")
+	assert.Equal(t, true, errors.Is(err, scan.ErrDuplicateKey))
+	assert.Equal(t, `duplicate key 1 in column "id"`, err.Error())
+
+	rows = q(t, "SELECT 1 AS id")
+	defer rows.Close()
+	_, err = scan.Map[int64, User](rows, "user_id")
+	assert.Equal(t, `scan: key column "user_id" not found`, err.Error())
+
+	rows = q(t, "SELECT NULL AS id, 'Ann' AS name")
+	defer rows.Close()
+	_, err = scan.Map[int64, User](rows, "id")
+	assert.Equal(t, true, errors.Is(err, scan.ErrNullKey))
+
+	rows = q(t, "SELECT NULL AS id, 'Ann' AS name UNION ALL SELECT 2, 'Bob'")
+	defer rows.Close()
+	byPointer, err := scan.Map[*int64, string](rows, "id")
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(byPointer))
+
+	rows = q(t, "SELECT NULL AS id, 'Ann' AS name UNION ALL SELECT 2, 'Bob'")
+	defer rows.Close()
+	byNull, err := scan.Map[sql.NullInt64, string](rows, "id")
+	require.NoError(t, err)
+	assert.Equal(t, map[sql.NullInt64]string{{}: "Ann", {Int64: 2, Valid: true}: "Bob"}, byNull)
+}
+
+func TestMapSlice(t *testing.T) {
+	type Order struct {
+		ID    int64 `db:"id"`
+		Total float64
+	}
+	rows := q(t, "SELECT 1 AS user_id, 10 AS id, 1.5 AS total UNION ALL SELECT 2, 11, 2.5 UNION ALL SELECT 1, 12, 3.5")
+	defer rows.Close()
+	orders, err := scan.MapSlice[int64, Order](rows, "user_id")
+	require.NoError(t, err)
+	assert.Equal(t, map[int64][]Order{
+		1: {{ID: 10, Total: 1.5}, {ID: 12, Total: 3.5}},
+		2: {{ID: 11, Total: 2.5}},
+	}, orders)
+
+	rows = q(t, "SELECT 'x' AS id, 1 AS n")
+	defer rows.Close()
+	_, err = scan.MapSlice[int64, int](rows, "id")
+	require.Error(t, err)
+}
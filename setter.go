@@ -0,0 +1,93 @@
+package scan
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Types take over the assignment of their columns by implementing one of two
+// interfaces. ColumnSetter suits types with no destinations to hand out, such
+// as attribute bags, as it is called with each value. RowScanner suits types
+// that do have destinations and want r.Scan to fill them without reflection.
+
+// ColumnSetter is implemented by types that assign every column themselves,
+// such as attribute bags or wrappers without settable fields. When *T
+// implements it, Row and Rows call ScanColumn for each column of each row
+// instead of mapping columns to fields.
+//
+// As with sql.Scanner, reference types such as []byte passed in src are only
+// valid until ScanColumn returns and must be copied to be retained.
+type ColumnSetter interface {
+	ScanColumn(name string, src any) error
+}
+
+// RowScanner is implemented by types that build their own r.Scan destinations.
+// When *T implements it, Row and Rows skip reflection entirely: ScanDest is
+// called once per row and its pointers are passed to r.Scan as they are,
+// without the conversions and NULL handling of this package. Wrap a
+// destination with Nullable to have NULL set it to its zero value.
+// RowScanner takes precedence over ColumnSetter.
+type RowScanner interface {
+	ScanDest(cols []string) ([]any, error)
+}
+
+var (
+	columnSetterType = reflect.TypeFor[ColumnSetter]()
+	rowScannerType   = reflect.TypeFor[RowScanner]()
+)
+
+// implementsSetter reports whether pointers to typ implement ColumnSetter.
+func implementsSetter(typ reflect.Type) bool {
+	return reflect.PointerTo(typ).Implements(columnSetterType)
+}
+
+// setterPointers returns the r.Scan destinations for item, whose address
+// implements ColumnSetter.
+func setterPointers(item reflect.Value, cols []string) []any {
+	s := item.Addr().Interface().(ColumnSetter)
+	pointers := make([]any, len(cols))
+	for i, col := range cols {
+		pointers[i] = columnSetterScanner{setter: s, column: col}
+	}
+	return pointers
+}
+
+// scanDirect scans rows through the RowScanner implementation of the values
+// next returns.
+func scanDirect(r *sql.Rows, sc *Scanner, cols []string, key *keyColumn, next func() any) error {
+	for r.Next() {
+		item := next()
+		if item == nil {
+			break
+		}
+		dests, err := any(item).(RowScanner).ScanDest(cols)
+		if err != nil {
+			return err
+		}
+		if len(dests) != len(cols) {
+			return fmt.Errorf("scan: %T.ScanDest returned %d destinations for %d columns", item, len(dests), len(cols))
+		}
+		for i, dest := range dests {
+			// destinations wrapped with Nullable convert with the options in use
+			if n, ok := dest.(nullable); ok && n.scanner == nil {
+				n.scanner = sc
+				dests[i] = n
+			}
+		}
+		if err := key.scan(r, sc, dests); err != nil {
+			return err
+		}
+	}
+	return r.Err()
+}
+
+// columnSetterScanner forwards the value of one column to a ColumnSetter.
+type columnSetterScanner struct {
+	setter ColumnSetter
+	column string
+}
+
+func (c columnSetterScanner) Scan(src any) error {
+	return c.setter.ScanColumn(c.column, src)
+}
@@ -0,0 +1,43 @@
+package scan
+
+import (
+	"reflect"
+	"strings"
+)
+
+// splitScanner scans a delimited column, such as a MySQL SET or a
+// GROUP_CONCAT result, into a slice or array, converting every element on its own.
+// An empty column gives an empty slice and NULL gives a nil slice.
+type splitScanner struct {
+	dest    reflect.Value
+	sep     string
+	scanner *Scanner
+}
+
+func (s splitScanner) Scan(src any) error {
+	if src == nil {
+		s.dest.SetZero()
+		return nil
+	}
+
+	dest := s.dest
+	if dest.Kind() == reflect.Pointer {
+		dest = reflect.New(dest.Type().Elem()).Elem()
+	}
+
+	// the parts are converted like the elements of an array literal
+	parts := pgArray{}
+	if str := asString(src); str != "" {
+		for _, part := range strings.Split(str, s.sep) {
+			parts = append(parts, part)
+		}
+	}
+	if err := s.scanner.assignPgArray(dest, parts); err != nil {
+		return &ScanError{Value: src, Type: s.dest.Type(), Err: err}
+	}
+
+	if s.dest.Kind() == reflect.Pointer {
+		s.dest.Set(dest.Addr())
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package scan
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplitScanner(t *testing.T) {
+	var (
+		strs []string
+		ints []int
+		ptr  *[]string
+		arr  [3]float64
+	)
+	tests := []struct {
+		dest    any
+		sep     string
+		src     any
+		want    any
+		wanterr string
+	}{
+		{dest: &strs, sep: ",", src: []byte("a,b,c"), want: []string{"a", "b", "c"}},
+		{dest: &strs, sep: ", ", src: "a, b", want: []string{"a", "b"}},
+		{dest: &strs, sep: ",", src: []byte(""), want: []string{}},
+		{dest: &strs, sep: ",", src: nil, want: []string(nil)},
+		{dest: &ints, sep: ",", src: "1,2,3", want: []int{1, 2, 3}},
+		{dest: &ints, sep: ",", src: int64(7), want: []int{7}},
+		{dest: &ints, sep: ",", src: "1,x", wanterr: `converting driver.Value type string ("1,x") to a []int: element 1: converting driver.Value type string ("x") to a int: invalid syntax`},
+		{dest: &arr, sep: ",", src: "1,2.5,3", want: [3]float64{1, 2.5, 3}},
+		{dest: &arr, sep: ",", src: "1,2", wanterr: `converting driver.Value type string ("1,2") to a [3]float64: got 2 elements, want 3`},
+		{dest: &ptr, sep: "|", src: "a|b", want: &[]string{"a", "b"}},
+		{dest: &ptr, sep: "|", src: nil, want: (*[]string)(nil)},
+	}
+	for _, tt := range tests {
+		dv := reflect.ValueOf(tt.dest).Elem()
+		err := splitScanner{dest: dv, sep: tt.sep, scanner: defaultScanner}.Scan(tt.src)
+		errstr := ""
+		if err != nil {
+			errstr = err.Error()
+		}
+		if errstr != tt.wanterr {
+			t.Errorf("split %#v into %s: got error %q, want %q", tt.src, dv.Type(), errstr, tt.wanterr)
+			continue
+		}
+		if tt.wanterr != "" {
+			var se *ScanError
+			if !errors.As(err, &se) {
+				t.Errorf("want *ScanError, got %T", err)
+			}
+			continue
+		}
+		if got := dv.Interface(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("split %#v into %s: got %#v, want %#v", tt.src, dv.Type(), got, tt.want)
+		}
+	}
+}
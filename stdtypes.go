@@ -0,0 +1,135 @@
+package scan
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errInvalidUUID = errors.New("invalid UUID")
+
+// convertStdType handles destinations from the standard library that have no
+// natural driver.Value representation. It reports whether dest was handled.
+func convertStdType(dest, src any) (bool, error) {
+	var text string
+	var raw []byte
+	switch s := src.(type) {
+	case string:
+		text = s
+	case []byte:
+		text, raw = string(s), s
+	default:
+		return false, nil
+	}
+
+	var err error
+	switch d := dest.(type) {
+	case *time.Duration:
+		*d, err = parseDuration(text)
+	case *netip.Addr:
+		*d, err = parseAddr(text, raw)
+	case *netip.Prefix:
+		*d, err = netip.ParsePrefix(strings.TrimSpace(text))
+	case *net.IP:
+		var addr netip.Addr
+		if addr, err = parseAddr(text, raw); err == nil {
+			*d = net.IP(addr.AsSlice())
+		}
+	default:
+		return false, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("converting driver.Value type %T (%q) to a %T: %v", src, text, dest, strconvErr(err))
+	}
+	return true, nil
+}
+
+// parseDuration accepts a count of nanoseconds or a time.ParseDuration string such as "1h30m".
+func parseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(n), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// parseAddr accepts a textual IP address, or the 4 or 16 byte binary form
+// produced by functions such as MySQL's INET6_ATON. raw is nil for string
+// sources. A 4 or 16 byte raw value that is not a textual address is always
+// read as binary, whatever its bytes.
+func parseAddr(text string, raw []byte) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(text))
+	if err == nil {
+		return addr, nil
+	}
+	if len(raw) == net.IPv4len || len(raw) == net.IPv6len {
+		addr, _ = netip.AddrFromSlice(raw)
+		return addr, nil
+	}
+	return netip.Addr{}, err
+}
+
+// asFixedBytes returns the bytes of a string or []byte src holding exactly n
+// bytes. Textual UUIDs are also accepted when n is 16.
+func asFixedBytes(src any, n int) ([]byte, error) {
+	if n == 16 {
+		u, err := asUUID(src)
+		return u[:], err
+	}
+	var b []byte
+	switch s := src.(type) {
+	case string:
+		b = []byte(s)
+	case []byte:
+		b = s
+	default:
+		return nil, errors.New("unsupported type")
+	}
+	if len(b) != n {
+		return nil, fmt.Errorf("got %d bytes, want %d", len(b), n)
+	}
+	return b, nil
+}
+
+// asUUID accepts a 16 byte binary UUID or its textual form, with or without
+// hyphens, braces or a "urn:uuid:" prefix.
+func asUUID(src any) (u [16]byte, err error) {
+	var b []byte
+	switch s := src.(type) {
+	case string:
+		b = []byte(s)
+	case []byte:
+		b = s
+	default:
+		return u, errInvalidUUID
+	}
+	if len(b) == len(u) {
+		copy(u[:], b)
+		return u, nil
+	}
+
+	b = bytes.TrimSpace(b)
+	b = bytes.TrimPrefix(b, []byte("urn:uuid:"))
+	if len(b) == 38 && b[0] == '{' && b[37] == '}' {
+		b = b[1:37]
+	}
+	if len(b) == 36 {
+		if b[8] != '-' || b[13] != '-' || b[18] != '-' || b[23] != '-' {
+			return u, errInvalidUUID
+		}
+		b = bytes.ReplaceAll(b, []byte("-"), nil)
+	}
+	if len(b) != 32 {
+		return u, errInvalidUUID
+	}
+	if _, err := hex.Decode(u[:], b); err != nil {
+		return u, errInvalidUUID
+	}
+	return u, nil
+}
@@ -0,0 +1,70 @@
+package scan
+
+import (
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type uuidType [16]byte
+
+func TestConvertStdTypes(t *testing.T) {
+	uuid := uuidType{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+
+	tests := []struct {
+		s, d    any
+		want    any
+		wanterr string
+	}{
+		{s: int64(1500), d: new(time.Duration), want: 1500 * time.Nanosecond},
+		{s: "1h30m", d: new(time.Duration), want: 90 * time.Minute},
+		{s: []byte("250ms"), d: new(time.Duration), want: 250 * time.Millisecond},
+		{s: []byte("5400000000000"), d: new(time.Duration), want: 90 * time.Minute},
+		{s: "soon", d: new(time.Duration), wanterr: `converting driver.Value type string ("soon") to a *time.Duration: time: invalid duration "soon"`},
+
+		{s: "10.0.0.1", d: new(netip.Addr), want: netip.MustParseAddr("10.0.0.1")},
+		{s: []byte("2001:db8::1"), d: new(netip.Addr), want: netip.MustParseAddr("2001:db8::1")},
+		{s: []byte{10, 0, 0, 1}, d: new(netip.Addr), want: netip.MustParseAddr("10.0.0.1")},
+		{s: []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}, d: new(netip.Addr), want: netip.MustParseAddr("2001:db8::1")},
+		{s: "localhost", d: new(netip.Addr), wanterr: `converting driver.Value type string ("localhost") to a *netip.Addr: ParseAddr("localhost"): unable to parse IP`},
+		{s: "host", d: new(netip.Addr), wanterr: `converting driver.Value type string ("host") to a *netip.Addr: ParseAddr("host"): unable to parse IP`},
+		{s: []byte("ABCD"), d: new(netip.Addr), want: netip.MustParseAddr("65.66.67.68")},
+		{s: []byte("ABCD"), d: new(net.IP), want: net.IP(netip.MustParseAddr("65.66.67.68").AsSlice())},
+		{s: []byte("0123456789abcdef"), d: new(netip.Addr), want: netip.MustParseAddr("3031:3233:3435:3637:3839:6162:6364:6566")},
+		{s: []byte("hosts"), d: new(netip.Addr), wanterr: `converting driver.Value type []uint8 ("hosts") to a *netip.Addr: ParseAddr("hosts"): unable to parse IP`},
+		{s: "10.0.0.0/8", d: new(netip.Prefix), want: netip.MustParsePrefix("10.0.0.0/8")},
+		{s: []byte("10.0.0.1"), d: new(net.IP), want: net.IP{10, 0, 0, 1}},
+		{s: []byte{10, 0, 0, 1}, d: new(net.IP), want: net.IP{10, 0, 0, 1}},
+
+		{s: uuid[:], d: new(uuidType), want: uuid},
+		{s: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", d: new(uuidType), want: uuid},
+		{s: []byte("6BA7B8109DAD11D180B400C04FD430C8"), d: new([16]byte), want: [16]byte(uuid)},
+		{s: "{6ba7b810-9dad-11d1-80b4-00c04fd430c8}", d: new(uuidType), want: uuid},
+		{s: "urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8", d: new(uuidType), want: uuid},
+		{s: "6ba7b810+9dad-11d1-80b4-00c04fd430c8", d: new(uuidType), wanterr: `converting driver.Value type string ("6ba7b810+9dad-11d1-80b4-00c04fd430c8") to a scan.uuidType: invalid UUID`},
+		{s: []byte{1, 2, 3, 4}, d: new([4]byte), want: [4]byte{1, 2, 3, 4}},
+		{s: "abc", d: new([3]byte), want: [3]byte{'a', 'b', 'c'}},
+		{s: []byte{1, 2, 3}, d: new([4]byte), wanterr: `converting driver.Value type []uint8 ("\x01\x02\x03") to a [4]uint8: got 3 bytes, want 4`},
+		{s: int64(1), d: new([4]byte), wanterr: `converting driver.Value type int64 ("1") to a [4]uint8: unsupported type`},
+		{s: int64(1), d: new(uuidType), wanterr: `converting driver.Value type int64 ("1") to a scan.uuidType: invalid UUID`},
+	}
+	for _, tt := range tests {
+		err := convertAssign(tt.d, tt.s)
+		errstr := ""
+		if err != nil {
+			errstr = err.Error()
+		}
+		if errstr != tt.wanterr {
+			t.Errorf("convertAssign(%T, %v): got error %q, want %q", tt.d, tt.s, errstr, tt.wanterr)
+			continue
+		}
+		if tt.wanterr != "" {
+			continue
+		}
+		if got := reflect.ValueOf(tt.d).Elem().Interface(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("convertAssign(%T, %v): got %v, want %v", tt.d, tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package scan
+
+import (
+	"cmp"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// A TagSource returns the column name and options of a struct field, in the
+// syntax of the `db` tag, read from one of its struct tags. It reports false
+// when the field has no such tag. A name of "-" marks the field ignored: no
+// column is scanned into it and later tag sources are not consulted.
+type TagSource func(tag reflect.StructTag) (string, bool)
+
+// TagKey returns a TagSource reading the struct tag key, such as "db", used
+// by sqlx too, or "json". Options the `db` tag does not know, such as
+// omitempty, are ignored, and a tag of "-" marks the field ignored.
+func TagKey(key string) TagSource {
+	return func(tag reflect.StructTag) (string, bool) {
+		return tag.Lookup(key)
+	}
+}
+
+// GormTag is a TagSource reading the column of a gorm tag, as in
+// `gorm:"column:user_id;not null"`. Fields tagged `gorm:"-"` or
+// `gorm:"-:all"` are ignored.
+func GormTag(tag reflect.StructTag) (string, bool) {
+	for _, part := range strings.Split(tag.Get("gorm"), ";") {
+		switch strings.TrimSpace(part) {
+		case "-", "-:all":
+			return "-", true
+		}
+		key, value, ok := strings.Cut(part, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "column") {
+			return strings.TrimSpace(value), true
+		}
+	}
+	return "", false
+}
+
+var defaultTagSources = []TagSource{TagKey("db")}
+
+// lookupTag returns the tag of sf from the first tag source that has one.
+// The tag of an ignored field has only ignored set.
+func (sc *Scanner) lookupTag(sf reflect.StructField) (fieldTag, bool) {
+	sources := sc.tagSources
+	if sources == nil {
+		sources = defaultTagSources
+	}
+	for _, source := range sources {
+		if tag, ok := source(sf.Tag); ok {
+			if tag == "-" {
+				return fieldTag{ignored: true}, true
+			}
+			return parseTag(tag), true
+		}
+	}
+	return fieldTag{}, false
+}
+
+// structTag returns the tag of sf, the zero fieldTag when it has none.
+func (sc *Scanner) structTag(sf reflect.StructField) fieldTag {
+	ft, _ := sc.lookupTag(sf)
+	return ft
+}
+
+// ignoredPath reports whether a field on the path index into typ is ignored.
+func (sc *Scanner) ignoredPath(typ reflect.Type, index []int) bool {
+	for _, x := range index {
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		sf := typ.Field(x)
+		if sc.structTag(sf).ignored {
+			return true
+		}
+		typ = sf.Type
+	}
+	return false
+}
+
+// fieldTag holds the column name and options parsed from a `db` struct tag,
+// e.g. `db:"created_at,unixmilli"`.
+type fieldTag struct {
+	name string
+	// unit is set when the column holds a Unix timestamp counted in unit.
+	unit time.Duration
+	// split is the separator of a delimited column scanned into a slice.
+	split string
+	// codec lists the codecs applied to the raw value, separated by "|".
+	codec string
+	// extra marks the map field collecting columns that have no field of their own.
+	extra bool
+	// ignored marks a field tagged "-", which no column is scanned into.
+	ignored bool
+}
+
+// plain reports whether the tag has no options changing how the value is scanned.
+func (ft fieldTag) plain() bool {
+	return ft.unit == 0 && ft.split == "" && ft.codec == ""
+}
+
+var unixUnits = map[string]time.Duration{
+	"unix":      time.Second,
+	"unixmilli": time.Millisecond,
+	"unixmicro": time.Microsecond,
+	"unixnano":  time.Nanosecond,
+}
+
+// parseTag splits a struct tag into the column name and its options.
+// Unknown options are ignored.
+//
+// The separator of the split option may itself be a comma, as in
+// `db:"tags,split=,"`, and defaults to a comma when omitted. Separators longer
+// than one character that contain a comma are quoted with single quotes, as in
+// `db:"tags,split=', '"`.
+func parseTag(tag string) fieldTag {
+	name, opts, _ := strings.Cut(tag, ",")
+	ft := fieldTag{name: name}
+	for opts != "" {
+		if quoted, ok := strings.CutPrefix(opts, "split='"); ok {
+			if sep, rest, ok := strings.Cut(quoted, "'"); ok {
+				ft.split = cmp.Or(sep, ",")
+				opts = strings.TrimPrefix(rest, ",")
+				continue
+			}
+		}
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		switch key, value, _ := strings.Cut(opt, "="); key {
+		case "unix", "unixmilli", "unixmicro", "unixnano":
+			ft.unit = unixUnits[key]
+		case "extra":
+			ft.extra = true
+		case "codec":
+			ft.codec = value
+		case "split":
+			ft.split = value
+			if value == "" {
+				// the comma consumed by Cut was the separator
+				ft.split = ","
+				opts = strings.TrimPrefix(opts, ",")
+			}
+		}
+	}
+	return ft
+}
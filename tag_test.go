@@ -0,0 +1,113 @@
+package scan
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want fieldTag
+	}{
+		{"", fieldTag{}},
+		{"id", fieldTag{name: "id"}},
+		{"created_at,unix", fieldTag{name: "created_at", unit: time.Second}},
+		{"created_at,unixmilli", fieldTag{name: "created_at", unit: time.Millisecond}},
+		{",unixmicro", fieldTag{unit: time.Microsecond}},
+		{"created_at,unixnano,unknown", fieldTag{name: "created_at", unit: time.Nanosecond}},
+		{"tags,split=,", fieldTag{name: "tags", split: ","}},
+		{"tags,split=,,unix", fieldTag{name: "tags", split: ",", unit: time.Second}},
+		{"tags,split=;", fieldTag{name: "tags", split: ";"}},
+		{"tags,split=|,unix", fieldTag{name: "tags", split: "|", unit: time.Second}},
+		{"tags,split", fieldTag{name: "tags", split: ","}},
+		{"tags,split=', '", fieldTag{name: "tags", split: ", "}},
+		{"tags,split=', ',unix", fieldTag{name: "tags", split: ", ", unit: time.Second}},
+		{"tags,split=''", fieldTag{name: "tags", split: ","}},
+		{",extra", fieldTag{extra: true}},
+		{"doc,codec=base64|gzip", fieldTag{name: "doc", codec: "base64|gzip"}},
+	}
+	for _, tt := range tests {
+		if got := parseTag(tt.tag); got != tt.want {
+			t.Errorf("parseTag(%q) = %+v, want %+v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestLookupTag(t *testing.T) {
+	type Row struct {
+		DB      int `db:"id,unix" json:"ident"`
+		Gorm    int `gorm:"type:int;column:user_id;not null" json:"uid"`
+		JSON    int `json:"total,omitempty"`
+		Skipped int `db:"-" json:"skipped"`
+		GormOut int `gorm:"-" json:"gorm_out"`
+		GormAll int `gorm:"-:all" json:"gorm_all"`
+		None    int
+	}
+	sc := newScanner([]Option{WithTagSources(TagKey("db"), GormTag, TagKey("json"))})
+	tests := []struct {
+		sc     *Scanner
+		field  string
+		want   fieldTag
+		wantOK bool
+	}{
+		{sc, "DB", fieldTag{name: "id", unit: time.Second}, true},
+		{sc, "Gorm", fieldTag{name: "user_id"}, true},
+		{sc, "JSON", fieldTag{name: "total"}, true},
+		{sc, "Skipped", fieldTag{ignored: true}, true},
+		{sc, "GormOut", fieldTag{ignored: true}, true},
+		{sc, "GormAll", fieldTag{ignored: true}, true},
+		{sc, "None", fieldTag{}, false},
+		{defaultScanner, "Gorm", fieldTag{}, false},
+		{defaultScanner, "JSON", fieldTag{}, false},
+	}
+	for _, tt := range tests {
+		sf, _ := reflect.TypeFor[Row]().FieldByName(tt.field)
+		got, ok := tt.sc.lookupTag(sf)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("lookupTag(%s) = %+v, %v, want %+v, %v", tt.field, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestIgnoredFields(t *testing.T) {
+	type Inner struct {
+		Code string `db:"code"`
+	}
+	type Row struct {
+		ID       int    `db:"id"`
+		Name     string `db:"-"`
+		Password string `gorm:"-" json:"password"`
+		Hidden   Inner  `db:"-"`
+		Inner    `db:"-"`
+	}
+	tests := []struct {
+		name   string
+		opts   []Option
+		mapped []string
+	}{
+		// gorm tags are not read by default, so Password is matched by name
+		{"default", nil, []string{"id", "password"}},
+		{"tag sources", []Option{WithTagSources(TagKey("db"), GormTag, TagKey("json"))}, []string{"id"}},
+		{"mapper", []Option{WithTagSources(TagKey("db"), GormTag, TagKey("json")), WithMapper(Lower)}, []string{"id"}},
+	}
+	cols := []string{"id", "name", "password", "hidden", "code"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := newStructMapping(newScanner(tt.opts), reflect.TypeFor[Row](), cols)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var mapped []string
+			for i, f := range m.fields {
+				if f.index != nil {
+					mapped = append(mapped, cols[i])
+				}
+			}
+			if !reflect.DeepEqual(mapped, tt.mapped) {
+				t.Errorf("mapped columns = %q, want %q", mapped, tt.mapped)
+			}
+		})
+	}
+}
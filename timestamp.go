@@ -0,0 +1,113 @@
+package scan
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unixTime scans a Unix timestamp counted in unit into a time destination.
+// NULL and zero timestamps leave the destination at its zero value.
+type unixTime struct {
+	dest    any
+	unit    time.Duration
+	scanner *Scanner
+}
+
+func (u unixTime) Scan(src any) error {
+	if t, ok := src.(time.Time); ok {
+		return u.scanner.convertAssign(u.dest, t)
+	}
+	t, err := parseUnix(src, u.unit)
+	if err != nil {
+		return &ScanError{Value: src, Type: reflect.TypeOf(u.dest).Elem(), Err: err}
+	}
+	if t.IsZero() {
+		return u.scanner.convertAssign(u.dest, nil)
+	}
+	return u.scanner.convertAssign(u.dest, t)
+}
+
+// parseUnix converts an integer, float or decimal string src counted in unit
+// since the Unix epoch into a time. NULL and 0 give the zero time.
+func parseUnix(src any, unit time.Duration) (time.Time, error) {
+	var whole, frac int64
+	switch v := src.(type) {
+	case nil:
+		return time.Time{}, nil
+	case string:
+		return parseUnixString(v, unit)
+	case []byte:
+		return parseUnixString(string(v), unit)
+	}
+
+	rv := reflect.ValueOf(src)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		whole = rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt64 {
+			return time.Time{}, strconv.ErrRange
+		}
+		whole = int64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || f >= math.MaxInt64 || f < math.MinInt64 {
+			return time.Time{}, strconv.ErrRange
+		}
+		w := math.Trunc(f)
+		whole, frac = int64(w), int64((f-w)*float64(unit))
+	default:
+		return time.Time{}, errors.New("unsupported type")
+	}
+	return unixTimeOf(whole, frac, unit), nil
+}
+
+// parseUnixString parses a decimal string such as "1700000000" or "1700000000.250".
+// A string without digits is a syntax error rather than the zero time.
+func parseUnixString(s string, unit time.Duration) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	neg := strings.HasPrefix(intPart, "-")
+	if fracPart == "" && (intPart == "" || intPart == "-" || intPart == "+") {
+		return time.Time{}, strconv.ErrSyntax
+	}
+
+	var whole int64
+	if intPart != "" && intPart != "-" && intPart != "+" {
+		var err error
+		whole, err = strconv.ParseInt(intPart, 10, 64)
+		if err != nil {
+			return time.Time{}, strconvErr(err)
+		}
+	}
+
+	var frac int64
+	if fracPart != "" {
+		// nanosecond precision is the most a time.Time can hold
+		if len(fracPart) > 9 {
+			fracPart = fracPart[:9]
+		}
+		digits, err := strconv.ParseUint(fracPart, 10, 64)
+		if err != nil {
+			return time.Time{}, strconvErr(err)
+		}
+		frac = int64(digits) * int64(unit) / int64(math.Pow10(len(fracPart)))
+		if neg {
+			frac = -frac
+		}
+	}
+	return unixTimeOf(whole, frac, unit), nil
+}
+
+// unixTimeOf returns the time whole units plus frac nanoseconds after the Unix epoch.
+func unixTimeOf(whole, frac int64, unit time.Duration) time.Time {
+	if whole == 0 && frac == 0 {
+		return time.Time{}
+	}
+	perSecond := int64(time.Second / unit)
+	return time.Unix(whole/perSecond, whole%perSecond*int64(unit)+frac)
+}
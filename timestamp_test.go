@@ -0,0 +1,89 @@
+package scan
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestParseUnix(t *testing.T) {
+	tests := []struct {
+		src     any
+		unit    time.Duration
+		want    time.Time
+		wanterr string
+	}{
+		{src: int64(1700000000), unit: time.Second, want: time.Unix(1700000000, 0)},
+		{src: int32(1700000000), unit: time.Second, want: time.Unix(1700000000, 0)},
+		{src: uint64(1700000000), unit: time.Second, want: time.Unix(1700000000, 0)},
+		{src: int64(1700000000123), unit: time.Millisecond, want: time.UnixMilli(1700000000123)},
+		{src: int64(1700000000123456), unit: time.Microsecond, want: time.UnixMicro(1700000000123456)},
+		{src: int64(1700000000123456789), unit: time.Nanosecond, want: time.Unix(0, 1700000000123456789)},
+		{src: int64(-1500), unit: time.Millisecond, want: time.UnixMilli(-1500)},
+		{src: 1700000000.5, unit: time.Second, want: time.Unix(1700000000, 500000000)},
+		{src: "1700000000", unit: time.Second, want: time.Unix(1700000000, 0)},
+		{src: []byte("1700000000.250"), unit: time.Second, want: time.Unix(1700000000, 250000000)},
+		{src: []byte("1700000000123.5"), unit: time.Millisecond, want: time.Unix(1700000000, 123500000)},
+		{src: "-1.5", unit: time.Second, want: time.Unix(-2, 500000000)},
+		{src: " 42 ", unit: time.Second, want: time.Unix(42, 0)},
+		{src: nil, unit: time.Second},
+		{src: int64(0), unit: time.Second},
+		{src: "0.000", unit: time.Second},
+		{src: "soon", unit: time.Second, wanterr: "invalid syntax"},
+		{src: "", unit: time.Second, wanterr: "invalid syntax"},
+		{src: []byte(" "), unit: time.Second, wanterr: "invalid syntax"},
+		{src: "-", unit: time.Second, wanterr: "invalid syntax"},
+		{src: "+", unit: time.Second, wanterr: "invalid syntax"},
+		{src: ".", unit: time.Second, wanterr: "invalid syntax"},
+		{src: uint64(1 << 63), unit: time.Second, wanterr: "value out of range"},
+		{src: true, unit: time.Second, wanterr: "unsupported type"},
+	}
+	for _, tt := range tests {
+		got, err := parseUnix(tt.src, tt.unit)
+		errstr := ""
+		if err != nil {
+			errstr = err.Error()
+		}
+		if errstr != tt.wanterr {
+			t.Errorf("parseUnix(%v, %v): got error %q, want %q", tt.src, tt.unit, errstr, tt.wanterr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseUnix(%v, %v): got %v, want %v", tt.src, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestUnixTimeScan(t *testing.T) {
+	sc := newScanner([]Option{WithUTC()})
+
+	var tv time.Time
+	if err := (unixTime{dest: &tv, unit: time.Second, scanner: sc}).Scan(int64(1700000000)); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Unix(1700000000, 0).UTC(); tv != want {
+		t.Errorf("got %v, want %v", tv, want)
+	}
+
+	tp := &tv
+	if err := (unixTime{dest: &tp, unit: time.Second, scanner: sc}).Scan(int64(0)); err != nil || tp != nil {
+		t.Errorf("zero timestamp into *time.Time: got %v, %v", tp, err)
+	}
+
+	nt := sql.Null[time.Time]{Valid: true}
+	if err := (unixTime{dest: &nt, unit: time.Millisecond, scanner: sc}).Scan(nil); err != nil || nt.Valid {
+		t.Errorf("NULL into sql.Null[time.Time]: got %v, %v", nt, err)
+	}
+
+	err := (unixTime{dest: &tv, unit: time.Second, scanner: sc}).Scan("soon")
+	if want := `converting driver.Value type string ("soon") to a time.Time: invalid syntax`; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+
+	var se *ScanError
+	if err := (unixTime{dest: &tv, unit: time.Second, scanner: sc}).Scan(""); !errors.As(err, &se) || !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("empty timestamp: got error %v, want a ScanError wrapping strconv.ErrSyntax", err)
+	}
+}